@@ -6,6 +6,10 @@ import (
 	"DC_NewsSender/internal/db/repositories"
 	"DC_NewsSender/internal/telegram"
 	"DC_NewsSender/pkg/configuration"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -20,6 +24,8 @@ type Config struct {
 	DBName         string `mapstructure:"POSTGRES_DB"`
 	DBPort         uint16 `mapstructure:"POSTGRES_PORT"`
 	Debug          bool   `mapstructure:"DEBUG"`
+	Approvers      string `mapstructure:"APPROVERS"`
+	ApprovalTTL    string `mapstructure:"APPROVAL_TTL"`
 }
 
 var (
@@ -41,6 +47,40 @@ func configureMaster(provider *repositories.Provider, masterId int64) error {
 	return nil
 }
 
+// parseApprovers parses the comma separated ids of APPROVERS.
+func parseApprovers(value string) ([]int64, error) {
+	var approvers []int64
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse APPROVERS: %w", err)
+		}
+		approvers = append(approvers, id)
+	}
+
+	return approvers, nil
+}
+
+// parseApprovalTTL parses APPROVAL_TTL, e.g. "12h", the default is used if it is empty.
+func parseApprovalTTL(value string) (time.Duration, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+
+	ttl, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("failed to parse APPROVAL_TTL %q", value)
+	}
+
+	return ttl, nil
+}
+
 func init() {
 	cfg, err := configuration.New[Config]()
 	if err != nil {
@@ -69,11 +109,23 @@ func init() {
 		logger.Panic(err.Error())
 	}
 
+	approvers, err := parseApprovers(env.Approvers)
+	if err != nil {
+		logger.Panic(err.Error())
+	}
+
+	approvalTTL, err := parseApprovalTTL(env.ApprovalTTL)
+	if err != nil {
+		logger.Panic(err.Error())
+	}
+
 	bot, err = telegram.CreateBotCore(&telegram.BotConfig{
-		Token:  env.TgToken,
-		Db:     provider,
-		Logger: logger,
-		Debug:  env.Debug})
+		Token:       env.TgToken,
+		Db:          provider,
+		Logger:      logger,
+		Debug:       env.Debug,
+		Approvers:   approvers,
+		ApprovalTTL: approvalTTL})
 	if err != nil {
 		logger.Panic(err.Error())
 	}
@@ -80,6 +80,9 @@ func (db *postgresDatabase) migrateDatabase() error {
 	if err := db.Connection.AutoMigrate(&models.Admin{}); err != nil {
 		return err
 	}
+	if err := db.migrateRoleGrants(); err != nil {
+		return err
+	}
 	if err := db.Connection.AutoMigrate(&models.Language{}); err != nil {
 		return err
 	}
@@ -89,6 +92,74 @@ func (db *postgresDatabase) migrateDatabase() error {
 	if err := db.Connection.AutoMigrate(&models.Chat{}); err != nil {
 		return err
 	}
+	if err := db.migrateChatGroups(); err != nil {
+		return err
+	}
+	if err := db.Connection.AutoMigrate(&models.Message{}); err != nil {
+		return err
+	}
+	if err := db.Connection.AutoMigrate(&models.MessageMedia{}); err != nil {
+		return err
+	}
+	if err := db.Connection.AutoMigrate(&models.MessageVariant{}); err != nil {
+		return err
+	}
+	if err := db.Connection.AutoMigrate(&models.Schedule{}); err != nil {
+		return err
+	}
+	if err := db.Connection.AutoMigrate(&models.Broadcast{}); err != nil {
+		return err
+	}
+	if err := db.Connection.AutoMigrate(&models.FailedDelivery{}); err != nil {
+		return err
+	}
+	if err := db.Connection.AutoMigrate(&models.Delivery{}); err != nil {
+		return err
+	}
+	if err := db.Connection.AutoMigrate(&models.PendingChat{}); err != nil {
+		return err
+	}
+	if err := db.Connection.AutoMigrate(&models.Approval{}); err != nil {
+		return err
+	}
+	if err := db.Connection.AutoMigrate(&models.AuditEntry{}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// migrateChatGroups moves the single group of chats added before a chat could be
+// a member of several groups to the chat_groups table.
+func (db *postgresDatabase) migrateChatGroups() error {
+	migrator := db.Connection.Migrator()
+
+	if migrator.HasColumn(&models.Chat{}, "group_id") {
+		if err := db.Connection.Exec("INSERT INTO chat_groups (chat_id, group_id) SELECT id, group_id FROM chats WHERE group_id IS NOT NULL AND group_id <> 0 ON CONFLICT DO NOTHING").Error; err != nil {
+			return err
+		}
+		if err := migrator.DropColumn(&models.Chat{}, "group_id"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateRoleGrants creates the role_grants table and gives the admins added before
+// roles existed the editor and sender roles, so they keep stashing and sending messages.
+func (db *postgresDatabase) migrateRoleGrants() error {
+	created := !db.Connection.Migrator().HasTable(&models.RoleGrant{})
+
+	if err := db.Connection.AutoMigrate(&models.RoleGrant{}); err != nil {
+		return err
+	}
+
+	if created {
+		if err := db.Connection.Exec("INSERT INTO role_grants (admin_id, role, group_id) SELECT id, role, 0 FROM admins, (VALUES ('editor'), ('sender')) AS roles (role) WHERE is_master IS NOT TRUE").Error; err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Approval is a broadcast requested by an admin who is not a master,
+// it starts once an approver approves it.
+type Approval struct {
+	Id          uint64    `gorm:"primaryKey"`
+	MessageId   uint64    `gorm:"column:message_id"`
+	Target      string    `gorm:"column:target"`
+	InitiatorId int64     `gorm:"column:initiator_id"`
+	ReviewerId  int64     `gorm:"column:reviewer_id"`
+	Status      string    `gorm:"column:status;index"`
+	Result      string    `gorm:"column:result"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	ExpiresAt   time.Time `gorm:"column:expires_at;index"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// AuditEntry records an action of an admin: a command, a stash or an answer to a broadcast request.
+type AuditEntry struct {
+	Id          uint64            `gorm:"primaryKey"`
+	InitiatorId int64             `gorm:"column:initiator_id;index"`
+	Command     string            `gorm:"column:command"`
+	Arguments   map[string]string `gorm:"column:arguments;serializer:json"`
+	Result      string            `gorm:"column:result"`
+	Error       string            `gorm:"column:error"`
+	CreatedAt   time.Time         `gorm:"column:created_at;index"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type Broadcast struct {
+	Id          uint64    `gorm:"primaryKey"`
+	MessageId   uint64    `gorm:"column:message_id"`
+	InitiatorId int64     `gorm:"column:initiator_id"`
+	Status      string    `gorm:"column:status"`
+	Total       int       `gorm:"column:total"`
+	Sent        int       `gorm:"column:sent"`
+	Failed      int       `gorm:"column:failed"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	// Target selects the chats, e.g. "groups=2,3;lang=1".
+	Target string `gorm:"column:target;not null;default:''"`
+}
@@ -1,11 +1,17 @@
 package models
 
+import "time"
+
 type Chat struct {
 	Id         int64    `gorm:"primaryKey;autoIncrement:false"`
 	Name       string   `gorm:"column:name"`
 	Language   Language `gorm:"foreignKey:LanguageId"`
 	LanguageId uint64   `gorm:"column:language_id"`
-	Group      Group    `gorm:"foreignKey:GroupId"`
-	GroupId    uint64   `gorm:"column:group_id"`
+	Groups     []Group  `gorm:"many2many:chat_groups"`
 	IsActive   bool     `gorm:"column:is_active;default:false"`
+	// DeactivatedReason is the delivery error the chat was deactivated by.
+	DeactivatedReason string     `gorm:"column:deactivated_reason;not null;default:''"`
+	DeactivatedAt     *time.Time `gorm:"column:deactivated_at"`
+	// Variables are the custom values of {{var "key"}} placeholders for this chat.
+	Variables map[string]string `gorm:"column:variables;serializer:json"`
 }
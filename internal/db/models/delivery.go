@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type Delivery struct {
+	Id                uint64 `gorm:"primaryKey"`
+	BroadcastId       uint64 `gorm:"column:broadcast_id;index"`
+	ChatId            int64  `gorm:"column:chat_id"`
+	GroupId           uint64 `gorm:"column:group_id"`
+	LanguageId        uint64 `gorm:"column:language_id"`
+	TelegramMessageId int    `gorm:"column:telegram_message_id"`
+	// TelegramMessageIds holds every message of the delivery, an album consists of several.
+	TelegramMessageIds []int `gorm:"column:telegram_message_ids;serializer:json"`
+	// MessageType is the type of the delivered message, the stashed one may have changed since.
+	MessageType int       `gorm:"column:message_type;default:0"`
+	Status      string    `gorm:"column:status"`
+	Error       string    `gorm:"column:error"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type FailedDelivery struct {
+	Id          uint64    `gorm:"primaryKey"`
+	BroadcastId uint64    `gorm:"column:broadcast_id;index"`
+	ChatId      int64     `gorm:"column:chat_id"`
+	Error       string    `gorm:"column:error"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+}
@@ -3,4 +3,6 @@ package models
 type Language struct {
 	Id   uint64 `gorm:"primaryKey"`
 	Name string `gorm:"column:name"`
+	// FallbackId is the language chats of this language get when a message has no text in it.
+	FallbackId *uint64 `gorm:"column:fallback_id"`
 }
@@ -0,0 +1,30 @@
+package models
+
+import tele "gopkg.in/telebot.v3"
+
+type Message struct {
+	Id        uint64           `gorm:"primaryKey;autoIncrement:false"`
+	ParseMode string           `gorm:"column:parse_mode;not null;default:entities"`
+	Media     []MessageMedia   `gorm:"foreignKey:MessageId;constraint:OnDelete:CASCADE"`
+	Variants  []MessageVariant `gorm:"foreignKey:MessageId;constraint:OnDelete:CASCADE"`
+}
+
+type MessageVariant struct {
+	MessageId  uint64 `gorm:"primaryKey;autoIncrement:false"`
+	LanguageId uint64 `gorm:"primaryKey;autoIncrement:false"`
+	Text       string `gorm:"column:text"`
+	// Entities are the formatting entities of the text as sent by Telegram.
+	Entities tele.Entities `gorm:"column:entities;serializer:json"`
+	// Buttons are the rows of URL buttons of the inline keyboard.
+	Buttons [][]tele.InlineButton `gorm:"column:buttons;serializer:json"`
+}
+
+type MessageMedia struct {
+	Id        uint64 `gorm:"primaryKey"`
+	MessageId uint64 `gorm:"column:message_id;index"`
+	// LanguageId is the language the file belongs to, 0 for the shared default.
+	LanguageId uint64 `gorm:"column:language_id;not null;default:0"`
+	Position   int    `gorm:"column:position"`
+	Type       int    `gorm:"column:type"`
+	FileId     string `gorm:"column:file_id"`
+}
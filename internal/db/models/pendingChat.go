@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// PendingChat is a chat the bot has been added to, waiting for a master or manager to pick its language and group.
+type PendingChat struct {
+	Id         int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name       string    `gorm:"column:name"`
+	LanguageId uint64    `gorm:"column:language_id"`
+	AddedBy    int64     `gorm:"column:added_by"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// RoleGrant gives an admin a role, limited to the group unless GroupId is 0.
+type RoleGrant struct {
+	Id      uint64 `gorm:"primaryKey"`
+	AdminId int64  `gorm:"column:admin_id;uniqueIndex:idx_role_grant"`
+	Role    string `gorm:"column:role;uniqueIndex:idx_role_grant"`
+	GroupId uint64 `gorm:"column:group_id;uniqueIndex:idx_role_grant"`
+}
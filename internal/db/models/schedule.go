@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type Schedule struct {
+	Id          uint64    `gorm:"primaryKey"`
+	MessageId   uint64    `gorm:"column:message_id"`
+	GroupId     uint64    `gorm:"column:group_id"`
+	InitiatorId int64     `gorm:"column:initiator_id"`
+	SendAt      time.Time `gorm:"column:send_at;index"`
+	Status      string    `gorm:"column:status;index"`
+	Result      string    `gorm:"column:result"`
+}
@@ -16,6 +16,14 @@ func CreateProvider(connection *gorm.DB) *Provider {
 	}
 }
 
+// Transaction runs the function with a provider whose repositories work inside one transaction,
+// the transaction is rolled back if the function returns an error.
+func (provider *Provider) Transaction(fn func(provider *Provider) error) error {
+	return provider.gormConnection.Transaction(func(tx *gorm.DB) error {
+		return fn(CreateProvider(tx))
+	})
+}
+
 func (provider *Provider) CreateGroupRepo() IRepository[models.Group, uint64] {
 	repo := &Repository[models.Group, uint64]{
 		BaseRepository{
@@ -53,11 +61,114 @@ func (provider *Provider) CreateAdminsRepo() IRepository[models.Admin, int64] {
 	return repo
 }
 
+func (provider *Provider) CreateMessageRepo() IRepository[models.Message, uint64] {
+	repo := &Repository[models.Message, uint64]{
+		BaseRepository{
+			gormConnection: provider.gormConnection,
+		},
+	}
+	return repo
+}
+
+func (provider *Provider) CreateMessageVariantRepo() IRepository[models.MessageVariant, uint64] {
+	repo := &Repository[models.MessageVariant, uint64]{
+		BaseRepository{
+			gormConnection: provider.gormConnection,
+		},
+	}
+	return repo
+}
+
+func (provider *Provider) CreateMessageMediaRepo() IRepository[models.MessageMedia, uint64] {
+	repo := &Repository[models.MessageMedia, uint64]{
+		BaseRepository{
+			gormConnection: provider.gormConnection,
+		},
+	}
+	return repo
+}
+
+func (provider *Provider) CreateScheduleRepo() IRepository[models.Schedule, uint64] {
+	repo := &Repository[models.Schedule, uint64]{
+		BaseRepository{
+			gormConnection: provider.gormConnection,
+		},
+	}
+	return repo
+}
+
+func (provider *Provider) CreateBroadcastRepo() IRepository[models.Broadcast, uint64] {
+	repo := &Repository[models.Broadcast, uint64]{
+		BaseRepository{
+			gormConnection: provider.gormConnection,
+		},
+	}
+	return repo
+}
+
+func (provider *Provider) CreateFailedDeliveryRepo() IRepository[models.FailedDelivery, uint64] {
+	repo := &Repository[models.FailedDelivery, uint64]{
+		BaseRepository{
+			gormConnection: provider.gormConnection,
+		},
+	}
+	return repo
+}
+
+func (provider *Provider) CreateDeliveryRepo() IRepository[models.Delivery, uint64] {
+	repo := &Repository[models.Delivery, uint64]{
+		BaseRepository{
+			gormConnection: provider.gormConnection,
+		},
+	}
+	return repo
+}
+
+func (provider *Provider) CreatePendingChatRepo() IRepository[models.PendingChat, int64] {
+	repo := &Repository[models.PendingChat, int64]{
+		BaseRepository{
+			gormConnection: provider.gormConnection,
+		},
+	}
+	return repo
+}
+
+func (provider *Provider) CreateRoleGrantRepo() IRepository[models.RoleGrant, uint64] {
+	repo := &Repository[models.RoleGrant, uint64]{
+		BaseRepository{
+			gormConnection: provider.gormConnection,
+		},
+	}
+	return repo
+}
+
+func (provider *Provider) CreateApprovalRepo() IRepository[models.Approval, uint64] {
+	repo := &Repository[models.Approval, uint64]{
+		BaseRepository{
+			gormConnection: provider.gormConnection,
+		},
+	}
+	return repo
+}
+
+func (provider *Provider) CreateAuditRepo() IRepository[models.AuditEntry, uint64] {
+	repo := &Repository[models.AuditEntry, uint64]{
+		BaseRepository{
+			gormConnection: provider.gormConnection,
+		},
+	}
+	return repo
+}
+
 type IRepository[T any, K comparable] interface {
 	FindById(id K) (*T, error)
 	FindBy(selector string, values ...string) (*[]T, error)
 	FindAll() (*[]T, error)
+	FindLatest(limit int, selector string, values ...string) (*[]T, error)
 	Add(value *T) (*T, error)
 	Update(value *T) (*T, error)
+	UpdateFields(value *T, fields ...string) (*T, error)
 	Remove(id K) error
+	RemoveBy(selector string, values ...string) error
+	ReplaceAssociation(value *T, name string, associated interface{}) error
 }
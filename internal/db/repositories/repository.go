@@ -3,6 +3,7 @@ package repositories
 import (
 	"errors"
 
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -27,7 +28,7 @@ func (repo *Repository[T, K]) FindBy(selector string, values ...string) (*[]T, e
 
 	var connection = repo.gormConnection
 
-	if result := connection.Preload(clause.Associations).Where(selector, values).Find(&selectedValue); result.Error != nil {
+	if result := connection.Preload(clause.Associations).Where(selector, toArgs(values)...).Find(&selectedValue); result.Error != nil {
 		return nil, result.Error
 	}
 
@@ -50,6 +51,24 @@ func (repo *Repository[T, K]) FindAll() (*[]T, error) {
 	return &values, nil
 }
 
+// FindLatest finds at most limit values matching the selector, the latest first.
+// An empty selector matches every value.
+func (repo *Repository[T, K]) FindLatest(limit int, selector string, values ...string) (*[]T, error) {
+	var selectedValues []T = make([]T, 0)
+
+	var connection = repo.gormConnection.Preload(clause.Associations)
+
+	if selector != "" {
+		connection = connection.Where(selector, toArgs(values)...)
+	}
+
+	if result := connection.Order("id desc").Limit(limit).Find(&selectedValues); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &selectedValues, nil
+}
+
 func (repo *Repository[T, K]) Add(value *T) (*T, error) {
 	if value == nil {
 		return nil, errors.New("null value provided")
@@ -78,6 +97,27 @@ func (repo *Repository[T, K]) Update(value *T) (*T, error) {
 	return value, nil
 }
 
+// UpdateFields saves only the fields of the existing value, it never inserts a removed one.
+// Its associations are left as they are in the database.
+func (repo *Repository[T, K]) UpdateFields(value *T, fields ...string) (*T, error) {
+	if value == nil {
+		return nil, errors.New("null value provided")
+	}
+
+	var connection = repo.gormConnection
+
+	result := connection.Model(value).Select(fields).Omit(clause.Associations).Updates(value)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return value, nil
+}
+
 func (repo *Repository[T, K]) Remove(id K) error {
 	var connection = repo.gormConnection
 	var value = new(T)
@@ -88,3 +128,35 @@ func (repo *Repository[T, K]) Remove(id K) error {
 
 	return nil
 }
+
+func (repo *Repository[T, K]) RemoveBy(selector string, values ...string) error {
+	var connection = repo.gormConnection
+	var value = new(T)
+
+	if err := connection.Where(selector, toArgs(values)...).Delete(value).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReplaceAssociation makes the associated values the only ones of the association,
+// an empty slice clears it.
+func (repo *Repository[T, K]) ReplaceAssociation(value *T, name string, associated interface{}) error {
+	var connection = repo.gormConnection
+
+	if err := connection.Model(value).Association(name).Replace(associated); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func toArgs(values []string) []interface{} {
+	var args = make([]interface{}, 0, len(values))
+	for _, value := range values {
+		args = append(args, value)
+	}
+
+	return args
+}
@@ -41,6 +41,16 @@ func (list *Cache[K, V]) Remove(key K) error {
 	return nil
 }
 
+// Take removes the value and returns it, only one of concurrent callers gets it.
+func (list *Cache[K, V]) Take(key K) *V {
+	if value, ok := list.List.LoadAndDelete(key); ok {
+		v := value.(V)
+		return &v
+	}
+
+	return nil
+}
+
 func (list *Cache[K, V]) Clear() {
 	list.List = sync.Map{}
 }
@@ -55,6 +65,18 @@ var Groups Cache[string, models.Group]
 
 var Messages Cache[uint64, models.Message]
 
+var Schedules Cache[uint64, models.Schedule]
+
+var Broadcasts Cache[uint64, models.Broadcast]
+
+var FailedDeliveries Cache[uint64, models.FailedDelivery]
+
+var PendingChats Cache[int64, models.PendingChat]
+
+var BroadcastRequests Cache[string, models.BroadcastRequest]
+
+var Approvals Cache[uint64, models.Approval]
+
 type ICache[K comparable, T any] interface {
 	Add(key K, value T)
 	Find(key K) *T
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"go.uber.org/zap"
@@ -33,6 +34,11 @@ func addAdmin(ctx context.Context) (string, error) {
 		return "", err
 	}
 
+	// The menu cannot be set before the user has started the bot, the commands still work then.
+	if err := controller.SetUserCommands(id); err != nil {
+		logger.Warn("Failed to set commands of user", zap.Error(err))
+	}
+
 	result := fmt.Sprintf("User [%d] %s has been added!", id, name)
 	logger.Debug("User added", zap.String("result", result))
 
@@ -78,6 +84,10 @@ func removeAdmin(ctx context.Context) (string, error) {
 		return "", err
 	}
 
+	if err := controller.ClearUserCommands(id); err != nil {
+		logger.Warn("Failed to clear commands of user", zap.Error(err))
+	}
+
 	logger.Debug("Removed user")
 
 	response := fmt.Sprintf("%d has been removed!", id)
@@ -106,11 +116,96 @@ func listAllAdmins(ctx context.Context) (string, error) {
 		return "", err
 	}
 
+	sort.Slice(admins, func(i, j int) bool { return admins[i].Id < admins[j].Id })
+
 	for _, admin := range admins {
-		response.WriteString(fmt.Sprintf("\n [%d] %s", admin.Id, admin.Name))
+		response.WriteString(fmt.Sprintf("\n [%d] %s | roles: %s", admin.Id, admin.Name, adminRoles(controller, &admin)))
 	}
 
 	logger.Debug("Listed all users", zap.String("response", response.String()))
 
 	return response.String(), nil
 }
+
+func grantRole(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var id int64 = ctx.Value(constants.RoleGrantArgs.Names[0]).(int64)
+	var role string = ctx.Value(constants.RoleGrantArgs.Names[1]).(string)
+	var groupId uint64 = ctx.Value(constants.RoleGrantArgs.Names[2]).(uint64)
+
+	logger := controller.Logger.With(
+		zap.String("function", "grantRole"),
+		zap.Int64("userID", user.Id),
+	)
+
+	logger.Debug("Granting role")
+
+	admin, err := controller.GrantRole(id, strings.TrimSpace(role), groupId)
+	if err != nil {
+		logger.Error("Failed to grant role", zap.Error(err))
+		return "", err
+	}
+
+	result := fmt.Sprintf("User [%d] %s roles: %s", admin.Id, admin.Name, adminRoles(controller, admin))
+	logger.Debug("Granted role", zap.String("result", result))
+
+	return result, nil
+}
+
+func revokeRole(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var id int64 = ctx.Value(constants.RoleRevokeArgs.Names[0]).(int64)
+	var role string = ctx.Value(constants.RoleRevokeArgs.Names[1]).(string)
+	var groupId uint64 = ctx.Value(constants.RoleRevokeArgs.Names[2]).(uint64)
+
+	logger := controller.Logger.With(
+		zap.String("function", "revokeRole"),
+		zap.Int64("userID", user.Id),
+	)
+
+	logger.Debug("Revoking role")
+
+	admin, err := controller.RevokeRole(id, strings.TrimSpace(role), groupId)
+	if err != nil {
+		logger.Error("Failed to revoke role", zap.Error(err))
+		return "", err
+	}
+
+	result := fmt.Sprintf("User [%d] %s roles: %s", admin.Id, admin.Name, adminRoles(controller, admin))
+	logger.Debug("Revoked role", zap.String("result", result))
+
+	return result, nil
+}
+
+// adminRoles lists the roles of the admin, a role limited to a group is followed by the group.
+func adminRoles(controller *controller.Controller, admin *models.User) string {
+	if admin.IsMaster {
+		return "master"
+	}
+
+	var roles []string
+	for _, grant := range admin.Roles {
+		if grant.GroupId == 0 {
+			roles = append(roles, grant.Role)
+			continue
+		}
+
+		name := "?"
+		if group, _ := controller.CreateGroupService().FindById(grant.GroupId); group != nil {
+			name = group.Name
+		}
+		roles = append(roles, fmt.Sprintf("%s in [%d] %s", grant.Role, grant.GroupId, name))
+	}
+
+	if len(roles) == 0 {
+		return "none"
+	}
+
+	sort.Strings(roles)
+
+	return strings.Join(roles, ", ")
+}
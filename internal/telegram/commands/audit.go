@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/controller"
+	"DC_NewsSender/internal/telegram/models"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+func auditLog(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var count = constants.AuditLogDefault
+	if value, ok := ctx.Value(constants.AuditLogArgs.Names[0]).(uint64); ok && value > 0 {
+		count = int(value)
+	}
+	if count > constants.AuditLogMax {
+		count = constants.AuditLogMax
+	}
+
+	var userId, _ = ctx.Value(constants.AuditLogArgs.Names[1]).(int64)
+
+	var userService = controller.CreateUserService()
+
+	logger := controller.Logger.With(
+		zap.String("function", "auditLog"),
+		zap.Int64("userID", user.Id),
+	)
+
+	logger.Debug("Listing audit log")
+
+	entries, err := controller.AuditLog(count, userId)
+	if err != nil {
+		logger.Error("Failed to find audit log", zap.Error(err))
+		return "", err
+	}
+
+	var response strings.Builder
+	response.WriteString("Audit Log:")
+
+	for _, entry := range entries {
+		var name string
+		if initiator, _ := userService.FindById(entry.InitiatorId); initiator != nil {
+			name = initiator.Name
+		}
+
+		outcome := entry.Result
+		if entry.Error != "" {
+			outcome = fmt.Sprintf("Error: %s", entry.Error)
+		}
+
+		response.WriteString(fmt.Sprintf("\n [%d] %s [%d] %s /%s%s",
+			entry.Id, entry.CreatedAt.Format(constants.ScheduleTimeLayout), entry.InitiatorId, name, entry.Command, formatAuditArguments(entry.Arguments)))
+		if outcome != "" {
+			response.WriteString(fmt.Sprintf(" -> %s", shorten(outcome, constants.AuditLogResultLimit)))
+		}
+	}
+
+	logger.Debug("Listed audit log", zap.Int("entries", len(entries)))
+
+	return response.String(), nil
+}
+
+// formatAuditArguments lists the arguments sorted by name, e.g. " chat_id=-100123 group_id=2".
+func formatAuditArguments(arguments map[string]string) string {
+	names := make([]string, 0, len(arguments))
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result strings.Builder
+	for _, name := range names {
+		result.WriteString(fmt.Sprintf(" %s=%s", name, arguments[name]))
+	}
+
+	return result.String()
+}
+
+// shorten puts the text on one line and cuts it to the limit.
+func shorten(text string, limit int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	if len(runes) <= limit {
+		return string(runes)
+	}
+
+	return string(runes[:limit]) + "..."
+}
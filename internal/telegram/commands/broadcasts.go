@@ -0,0 +1,278 @@
+package commands
+
+import (
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/controller"
+	"DC_NewsSender/internal/telegram/models"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+func listFailed(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var chatService = controller.CreateChatService()
+
+	logger := controller.Logger.With(
+		zap.String("function", "listFailed"),
+		zap.Int64("userID", user.Id),
+	)
+
+	logger.Debug("Listing failed deliveries")
+
+	failedDeliveries, err := controller.CreateFailedDeliveryService().FindAll()
+	if err != nil {
+		logger.Error("Failed to find failed deliveries", zap.Error(err))
+		return "", err
+	}
+
+	sort.Slice(failedDeliveries, func(i, j int) bool { return failedDeliveries[i].Id < failedDeliveries[j].Id })
+
+	var response strings.Builder
+	response.WriteString("Failed Deliveries:")
+
+	for _, failedDelivery := range failedDeliveries {
+		var name string
+		if chat, _ := chatService.FindById(failedDelivery.ChatId); chat != nil {
+			name = chat.Name
+		}
+		response.WriteString(fmt.Sprintf("\n [job %d] [%d] %s: %s", failedDelivery.BroadcastId, failedDelivery.ChatId, name, failedDelivery.Error))
+	}
+
+	logger.Debug("Listed failed deliveries", zap.String("response", response.String()))
+
+	return response.String(), nil
+}
+
+func retryFailed(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var jobId uint64 = ctx.Value(constants.FailedRetryArgs.Names[0]).(uint64)
+
+	logger := controller.Logger.With(
+		zap.String("function", "retryFailed"),
+		zap.Int64("userID", user.Id),
+	)
+
+	logger.Debug("Retrying failed deliveries")
+
+	if err := checkBroadcastPermission(controller, user, jobId); err != nil {
+		logger.Warn("broadcast not permitted", zap.Error(err))
+		return "", err
+	}
+
+	response, err := controller.RetryFailed(user.Id, jobId)
+	if err != nil {
+		logger.Error("Failed to retry failed deliveries", zap.Error(err))
+		return "", err
+	}
+
+	logger.Debug("Retried failed deliveries")
+
+	return response, nil
+}
+
+func broadcastStatus(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var jobId uint64 = ctx.Value(constants.BroadcastStatusArgs.Names[0]).(uint64)
+
+	var groupService = controller.CreateGroupService()
+	var langService = controller.CreateLanguageService()
+
+	logger := controller.Logger.With(
+		zap.String("function", "broadcastStatus"),
+		zap.Int64("userID", user.Id),
+	)
+
+	logger.Debug("Reporting broadcast status")
+
+	broadcast, _ := controller.CreateBroadcastService().FindById(jobId)
+	if broadcast == nil {
+		err := constants.ErrNotFound
+		logger.Error("Failed to find a broadcast", zap.Error(err))
+		return "", err
+	}
+
+	deliveries, _ := controller.CreateDeliveryService().FindBy("broadcast_id = ?", fmt.Sprint(broadcast.Id))
+
+	type key struct {
+		groupId    uint64
+		languageId uint64
+	}
+	counts := make(map[key]map[string]int)
+	totals := make(map[string]int)
+	var keys []key
+
+	for _, record := range deliveries {
+		k := key{groupId: record.GroupId, languageId: record.LanguageId}
+		if _, ok := counts[k]; !ok {
+			counts[k] = make(map[string]int)
+			keys = append(keys, k)
+		}
+		counts[k][record.Status]++
+		totals[record.Status]++
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].groupId != keys[j].groupId {
+			return keys[i].groupId < keys[j].groupId
+		}
+		return keys[i].languageId < keys[j].languageId
+	})
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("Broadcast [%d] of message [%d] to %s: %s", broadcast.Id, broadcast.MessageId, broadcast.Target, broadcast.Status))
+
+	for _, k := range keys {
+		var groupName, langName string
+		if group, _ := groupService.FindById(k.groupId); group != nil {
+			groupName = group.Name
+		} else if k.groupId == 0 {
+			groupName = "no group"
+		}
+		if lang, _ := langService.FindById(k.languageId); lang != nil {
+			langName = lang.Name
+		}
+
+		response.WriteString(fmt.Sprintf("\n [%d] %s / [%d] %s: %s", k.groupId, groupName, k.languageId, langName, formatDeliveryCounts(counts[k])))
+	}
+
+	response.WriteString(fmt.Sprintf("\nTotal: %s", formatDeliveryCounts(totals)))
+
+	logger.Debug("Reported broadcast status", zap.String("response", response.String()))
+
+	return response.String(), nil
+}
+
+func formatDeliveryCounts(counts map[string]int) string {
+	return fmt.Sprintf("%d sent, %d skipped, %d failed, %d recalled",
+		counts[models.DeliveryStatusSent], counts[models.DeliveryStatusSkipped], counts[models.DeliveryStatusFailed], counts[models.DeliveryStatusRecalled])
+}
+
+func editBroadcast(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var jobId uint64 = ctx.Value(constants.BroadcastEditArgs.Names[0]).(uint64)
+
+	logger := controller.Logger.With(
+		zap.String("function", "editBroadcast"),
+		zap.Int64("userID", user.Id),
+	)
+
+	logger.Debug("Editing broadcast")
+
+	if err := checkBroadcastPermission(controller, user, jobId); err != nil {
+		logger.Warn("broadcast not permitted", zap.Error(err))
+		return "", err
+	}
+
+	response, err := controller.EditBroadcast(user.Id, jobId)
+	if err != nil {
+		logger.Error("Failed to edit broadcast", zap.Error(err))
+		return "", err
+	}
+
+	logger.Debug("Edited broadcast")
+
+	return response, nil
+}
+
+func recallBroadcast(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var jobId uint64 = ctx.Value(constants.BroadcastRecallArgs.Names[0]).(uint64)
+
+	logger := controller.Logger.With(
+		zap.String("function", "recallBroadcast"),
+		zap.Int64("userID", user.Id),
+	)
+
+	logger.Debug("Recalling broadcast")
+
+	if err := checkBroadcastPermission(controller, user, jobId); err != nil {
+		logger.Warn("broadcast not permitted", zap.Error(err))
+		return "", err
+	}
+
+	response, err := controller.RecallBroadcast(user.Id, jobId)
+	if err != nil {
+		logger.Error("Failed to recall broadcast", zap.Error(err))
+		return "", err
+	}
+
+	logger.Debug("Recalled broadcast")
+
+	return response, nil
+}
+
+// checkBroadcastPermission lets users whose send roles are limited to groups
+// act only on broadcasts to these groups.
+func checkBroadcastPermission(controller *controller.Controller, user *models.User, jobId uint64) error {
+	if user.CanIn(models.PermissionSend, 0) {
+		return nil
+	}
+
+	broadcast, _ := controller.CreateBroadcastService().FindById(jobId)
+	if broadcast == nil {
+		return constants.ErrNotFound
+	}
+
+	target, err := models.ParseTarget(strings.Split(broadcast.Target, ";"))
+	if err != nil || !user.CanTarget(models.PermissionSend, target) {
+		return constants.ErrNoPermission
+	}
+
+	return nil
+}
+
+func listPending(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var userService = controller.CreateUserService()
+
+	logger := controller.Logger.With(
+		zap.String("function", "listPending"),
+		zap.Int64("userID", user.Id),
+	)
+
+	logger.Debug("Listing pending approvals")
+
+	approvals, err := controller.CreateApprovalService().FindAll()
+	if err != nil {
+		logger.Error("Failed to find approvals", zap.Error(err))
+		return "", err
+	}
+
+	sort.Slice(approvals, func(i, j int) bool { return approvals[i].Id < approvals[j].Id })
+
+	var response strings.Builder
+	response.WriteString("Pending Approvals:")
+
+	for _, approval := range approvals {
+		if approval.Status != models.ApprovalStatusPending {
+			continue
+		}
+
+		var name string
+		if initiator, _ := userService.FindById(approval.InitiatorId); initiator != nil {
+			name = initiator.Name
+		}
+		response.WriteString(fmt.Sprintf("\n [%d] message [%d] to %s by [%d] %s, expires at %s",
+			approval.Id, approval.MessageId, approval.Target, approval.InitiatorId, name, approval.ExpiresAt.Format(constants.ScheduleTimeLayout)))
+	}
+
+	logger.Debug("Listed pending approvals", zap.String("response", response.String()))
+
+	return response.String(), nil
+}
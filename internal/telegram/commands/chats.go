@@ -1,12 +1,16 @@
 package commands
 
 import (
+	db_models "DC_NewsSender/internal/db/models"
 	"DC_NewsSender/internal/telegram/constants"
 	"DC_NewsSender/internal/telegram/controller"
 	"DC_NewsSender/internal/telegram/models"
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"go.uber.org/zap"
@@ -37,12 +41,13 @@ func addChat(ctx context.Context) (string, error) {
 		return "", errors.New("language not found")
 	}
 
-	if group, err := groupService.FindById(groupId); group == nil {
+	group, err := groupService.FindById(groupId)
+	if group == nil {
 		logger.Error("Failed to find a group", zap.Error(err))
 		return "", errors.New("group not found")
 	}
 
-	if _, err := chatService.Add(&models.Chat{Id: id, Name: name, LanguageId: langId, GroupId: groupId}); err != nil {
+	if _, err := chatService.Add(&models.Chat{Id: id, Name: name, LanguageId: langId, Groups: []db_models.Group{db_models.Group(*group)}}); err != nil {
 		logger.Error("Failed to add a chat", zap.Error(err))
 		return "", err
 	}
@@ -101,11 +106,333 @@ func listAllChats(ctx context.Context) (string, error) {
 		return "", err
 	}
 
+	sort.Slice(chats, func(i, j int) bool { return chats[i].Id < chats[j].Id })
+
+	var langService = controller.CreateLanguageService()
+
 	for _, chat := range chats {
-		response.WriteString(fmt.Sprintf("\n [%d] %s", chat.Id, chat.Name))
+		status := chatStatus(chat)
+
+		langName := "?"
+		if lang, _ := langService.FindById(chat.LanguageId); lang != nil {
+			langName = lang.Name
+		}
+
+		response.WriteString(fmt.Sprintf("\n [%d] %s | %s | language: [%d] %s | groups: %s", chat.Id, chat.Name, status, chat.LanguageId, langName, chatGroups(chat)))
 	}
 
 	logger.Debug("Listed all chats", zap.String("response", response.String()))
 
 	return response.String(), nil
 }
+
+func activateChat(ctx context.Context) (string, error) {
+	var id int64 = ctx.Value(constants.ChatActivateArgs.Names[0]).(int64)
+
+	return setChatActive(ctx, "activateChat", id, true)
+}
+
+func deactivateChat(ctx context.Context) (string, error) {
+	var id int64 = ctx.Value(constants.ChatDeactivateArgs.Names[0]).(int64)
+
+	return setChatActive(ctx, "deactivateChat", id, false)
+}
+
+func setChatActive(ctx context.Context, function string, id int64, active bool) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var chatService = controller.CreateChatService()
+
+	logger := controller.Logger.With(
+		zap.String("function", function),
+		zap.Int64("userID", user.Id),
+		zap.Int64("chatID", id),
+	)
+
+	logger.Debug("Updating chat status", zap.Bool("active", active))
+
+	chat, err := chatService.FindById(id)
+	if chat == nil {
+		logger.Error("Failed to find a chat", zap.Error(err))
+		return "", errors.New("chat not found")
+	}
+
+	chat.IsActive = active
+	if active {
+		chat.DeactivatedReason, chat.DeactivatedAt = "", nil
+	}
+	if _, err := chatService.Update(chat); err != nil {
+		logger.Error("Failed to update a chat", zap.Error(err))
+		return "", err
+	}
+
+	status := "deactivated"
+	if active {
+		status = "activated"
+	}
+
+	result := fmt.Sprintf("Chat %s [%d] has been %s!", chat.Name, chat.Id, status)
+	logger.Debug("Updated chat status", zap.String("result", result))
+
+	return result, nil
+}
+
+func editChat(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var id int64 = ctx.Value(constants.ChatEditArgs.Names[0]).(int64)
+	var field string = ctx.Value(constants.ChatEditArgs.Names[1]).(string)
+	var value string = ctx.Value(constants.ChatEditArgs.Names[2]).(string)
+
+	var chatService = controller.CreateChatService()
+
+	logger := controller.Logger.With(
+		zap.String("function", "editChat"),
+		zap.Int64("userID", user.Id),
+		zap.Int64("chatID", id),
+	)
+
+	logger.Debug("Editing chat", zap.String("field", field), zap.String("value", value))
+
+	chat, err := chatService.FindById(id)
+	if chat == nil {
+		logger.Error("Failed to find a chat", zap.Error(err))
+		return "", errors.New("chat not found")
+	}
+
+	switch strings.ToLower(field) {
+	case "name":
+		if value == "" {
+			return "", errors.New("name cannot be empty")
+		}
+		chat.Name = value
+	case "language":
+		langId, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return "", errors.New("invalid language_id")
+		}
+
+		lang, err := controller.CreateLanguageService().FindById(langId)
+		if lang == nil {
+			logger.Error("Failed to find a language", zap.Error(err))
+			return "", errors.New("language not found")
+		}
+
+		// The preloaded association would overwrite the new id on save.
+		chat.LanguageId, chat.Language = lang.Id, db_models.Language(*lang)
+	default:
+		return "", errors.New("unknown field, use name or language")
+	}
+
+	if _, err := chatService.Update(chat); err != nil {
+		logger.Error("Failed to update a chat", zap.Error(err))
+		return "", err
+	}
+
+	result := fmt.Sprintf("Chat %s [%d] has been updated!", chat.Name, chat.Id)
+	logger.Debug("Edited chat", zap.String("result", result))
+
+	return result, nil
+}
+
+func addChatToGroup(ctx context.Context) (string, error) {
+	var chatId int64 = ctx.Value(constants.ChatAddToGroupArgs.Names[0]).(int64)
+	var groupId uint64 = ctx.Value(constants.ChatAddToGroupArgs.Names[1]).(uint64)
+
+	return setChatGroup(ctx, "addChatToGroup", chatId, groupId, true)
+}
+
+func removeChatFromGroup(ctx context.Context) (string, error) {
+	var chatId int64 = ctx.Value(constants.ChatRemoveFromGroupArgs.Names[0]).(int64)
+	var groupId uint64 = ctx.Value(constants.ChatRemoveFromGroupArgs.Names[1]).(uint64)
+
+	return setChatGroup(ctx, "removeChatFromGroup", chatId, groupId, false)
+}
+
+func setChatGroup(ctx context.Context, function string, chatId int64, groupId uint64, member bool) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var chatService = controller.CreateChatService()
+
+	logger := controller.Logger.With(
+		zap.String("function", function),
+		zap.Int64("userID", user.Id),
+		zap.Int64("chatID", chatId),
+		zap.Uint64("groupID", groupId),
+	)
+
+	logger.Debug("Updating chat groups", zap.Bool("member", member))
+
+	chat, err := chatService.FindById(chatId)
+	if chat == nil {
+		logger.Error("Failed to find a chat", zap.Error(err))
+		return "", errors.New("chat not found")
+	}
+
+	group, err := controller.CreateGroupService().FindById(groupId)
+	if group == nil {
+		logger.Error("Failed to find a group", zap.Error(err))
+		return "", errors.New("group not found")
+	}
+
+	if chat.InGroup(group.Id) == member {
+		if member {
+			return "", fmt.Errorf("chat %s [%d] is already in group %s [%d]", chat.Name, chat.Id, group.Name, group.Id)
+		}
+		return "", fmt.Errorf("chat %s [%d] is not in group %s [%d]", chat.Name, chat.Id, group.Name, group.Id)
+	}
+
+	groups := make([]db_models.Group, 0, len(chat.Groups)+1)
+	for _, existing := range chat.Groups {
+		if existing.Id != group.Id {
+			groups = append(groups, existing)
+		}
+	}
+	if member {
+		groups = append(groups, db_models.Group(*group))
+	}
+	chat.Groups = groups
+
+	if _, err := chatService.Update(chat); err != nil {
+		logger.Error("Failed to update a chat", zap.Error(err))
+		return "", err
+	}
+
+	result := fmt.Sprintf("Chat %s [%d] has been removed from group %s [%d]!", chat.Name, chat.Id, group.Name, group.Id)
+	if member {
+		result = fmt.Sprintf("Chat %s [%d] has been added to group %s [%d]!", chat.Name, chat.Id, group.Name, group.Id)
+	}
+	logger.Debug("Updated chat groups", zap.String("result", result))
+
+	return result, nil
+}
+
+// variableKey is the name of a chat variable, as in {{var "region"}}.
+var variableKey = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+func setChatVariable(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var id int64 = ctx.Value(constants.ChatVariableSetArgs.Names[0]).(int64)
+	var key string = ctx.Value(constants.ChatVariableSetArgs.Names[1]).(string)
+	var value string = ctx.Value(constants.ChatVariableSetArgs.Names[2]).(string)
+
+	var chatService = controller.CreateChatService()
+
+	logger := controller.Logger.With(
+		zap.String("function", "setChatVariable"),
+		zap.Int64("userID", user.Id),
+		zap.Int64("chatID", id),
+	)
+
+	logger.Debug("Setting chat variable", zap.String("key", key), zap.String("value", value))
+
+	if !variableKey.MatchString(key) {
+		return "", errors.New("key may only contain letters, digits and _")
+	}
+
+	chat, err := chatService.FindById(id)
+	if chat == nil {
+		logger.Error("Failed to find a chat", zap.Error(err))
+		return "", errors.New("chat not found")
+	}
+
+	// The cached chat shares the map, it is replaced instead of changed.
+	variables := make(map[string]string, len(chat.Variables)+1)
+	for k, v := range chat.Variables {
+		variables[k] = v
+	}
+
+	// An empty value removes the variable.
+	if value == "" {
+		delete(variables, key)
+	} else {
+		variables[key] = value
+	}
+	chat.Variables = variables
+
+	if _, err := chatService.Update(chat); err != nil {
+		logger.Error("Failed to update a chat", zap.Error(err))
+		return "", err
+	}
+
+	result := fmt.Sprintf("Variable %s of chat %s [%d] has been removed!", key, chat.Name, chat.Id)
+	if value != "" {
+		result = fmt.Sprintf("Variable %s of chat %s [%d] has been set!", key, chat.Name, chat.Id)
+	}
+	logger.Debug("Set chat variable", zap.String("result", result))
+
+	return result, nil
+}
+
+func listChatVariables(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var id int64 = ctx.Value(constants.ChatVariableListArgs.Names[0]).(int64)
+
+	logger := controller.Logger.With(
+		zap.String("function", "listChatVariables"),
+		zap.Int64("userID", user.Id),
+		zap.Int64("chatID", id),
+	)
+
+	logger.Debug("Listing chat variables")
+
+	chat, err := controller.CreateChatService().FindById(id)
+	if chat == nil {
+		logger.Error("Failed to find a chat", zap.Error(err))
+		return "", errors.New("chat not found")
+	}
+
+	keys := make([]string, 0, len(chat.Variables))
+	for key := range chat.Variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("Variables of chat %s [%d]:", chat.Name, chat.Id))
+	for _, key := range keys {
+		response.WriteString(fmt.Sprintf("\n %s = %s", key, chat.Variables[key]))
+	}
+
+	logger.Debug("Listed chat variables", zap.String("response", response.String()))
+
+	return response.String(), nil
+}
+
+// chatGroups lists the groups of the chat sorted by id.
+func chatGroups(chat models.Chat) string {
+	if len(chat.Groups) == 0 {
+		return "none"
+	}
+
+	groups := make([]db_models.Group, len(chat.Groups))
+	copy(groups, chat.Groups)
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Id < groups[j].Id })
+
+	names := make([]string, 0, len(groups))
+	for _, group := range groups {
+		names = append(names, fmt.Sprintf("[%d] %s", group.Id, group.Name))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// chatStatus describes whether the chat receives broadcasts and why not.
+func chatStatus(chat models.Chat) string {
+	if chat.IsActive {
+		return "active"
+	}
+
+	if chat.DeactivatedAt != nil {
+		return fmt.Sprintf("inactive since %s: %s", chat.DeactivatedAt.Format(constants.ScheduleTimeLayout), chat.DeactivatedReason)
+	}
+
+	return "inactive"
+}
@@ -3,10 +3,12 @@ package commands
 import (
 	"DC_NewsSender/internal/telegram/commands/middlewares"
 	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/controller"
 	"DC_NewsSender/internal/telegram/models"
 
 	"context"
 	"fmt"
+	"strings"
 )
 
 type Command struct {
@@ -24,14 +26,14 @@ var (
 			Description: fmt.Sprintf("Add %s", AdminGroup.Name),
 			Handler:     addAdmin,
 			Arguments:   constants.UserAddArgs,
-			Middlewares: []middlewares.Middleware{middlewares.IsMaster, middlewares.HasInput, middlewares.ParseInput},
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionMaster), middlewares.HasInput, middlewares.ParseInput},
 		},
 		{
 			Name:        AdminGroup.Remove,
 			Description: fmt.Sprintf("Remove %s", AdminGroup.Name),
 			Handler:     removeAdmin,
 			Arguments:   constants.UserRemoveArgs,
-			Middlewares: []middlewares.Middleware{middlewares.IsMaster, middlewares.HasInput, middlewares.ParseInput},
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionMaster), middlewares.HasInput, middlewares.ParseInput},
 		},
 		{
 			Name:        AdminGroup.List,
@@ -40,19 +42,33 @@ var (
 			Arguments:   constants.UserListArgs,
 			Middlewares: []middlewares.Middleware{},
 		},
+		{
+			Name:        "grantrole",
+			Description: fmt.Sprintf("Grant a role to %s", AdminGroup.Name),
+			Handler:     grantRole,
+			Arguments:   constants.RoleGrantArgs,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionMaster), middlewares.HasInput, middlewares.ParseInput},
+		},
+		{
+			Name:        "revokerole",
+			Description: fmt.Sprintf("Revoke a role of %s", AdminGroup.Name),
+			Handler:     revokeRole,
+			Arguments:   constants.RoleRevokeArgs,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionMaster), middlewares.HasInput, middlewares.ParseInput},
+		},
 		{
 			Name:        ChatGroup.Add,
 			Description: fmt.Sprintf("Add %s", ChatGroup.Name),
 			Handler:     addChat,
 			Arguments:   constants.ChatAddArgs,
-			Middlewares: []middlewares.Middleware{middlewares.IsMaster, middlewares.HasInput, middlewares.ParseInput},
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionManageChats), middlewares.HasInput, middlewares.ParseInput},
 		},
 		{
 			Name:        ChatGroup.Remove,
 			Description: fmt.Sprintf("Remove %s", ChatGroup.Name),
 			Handler:     removeChat,
 			Arguments:   constants.ChatRemoveArgs,
-			Middlewares: []middlewares.Middleware{middlewares.IsMaster, middlewares.HasInput, middlewares.ParseInput},
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionManageChats), middlewares.HasInput, middlewares.ParseInput},
 		},
 		{
 			Name:        ChatGroup.List,
@@ -61,19 +77,68 @@ var (
 			Arguments:   constants.ChatListArgs,
 			Middlewares: []middlewares.Middleware{},
 		},
+		{
+			Name:        "activatechat",
+			Description: fmt.Sprintf("Activate %s", ChatGroup.Name),
+			Handler:     activateChat,
+			Arguments:   constants.ChatActivateArgs,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionManageChats), middlewares.HasInput, middlewares.ParseInput},
+		},
+		{
+			Name:        "deactivatechat",
+			Description: fmt.Sprintf("Deactivate %s", ChatGroup.Name),
+			Handler:     deactivateChat,
+			Arguments:   constants.ChatDeactivateArgs,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionManageChats), middlewares.HasInput, middlewares.ParseInput},
+		},
+		{
+			Name:        "editchat",
+			Description: fmt.Sprintf("Edit name or language of %s", ChatGroup.Name),
+			Handler:     editChat,
+			Arguments:   constants.ChatEditArgs,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionManageChats), middlewares.HasInput, middlewares.ParseInput},
+		},
+		{
+			Name:        "addchattogroup",
+			Description: fmt.Sprintf("Add %s to %s", ChatGroup.Name, GroupGroup.Name),
+			Handler:     addChatToGroup,
+			Arguments:   constants.ChatAddToGroupArgs,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionManageChats), middlewares.HasInput, middlewares.ParseInput},
+		},
+		{
+			Name:        "removechatfromgroup",
+			Description: fmt.Sprintf("Remove %s from %s", ChatGroup.Name, GroupGroup.Name),
+			Handler:     removeChatFromGroup,
+			Arguments:   constants.ChatRemoveFromGroupArgs,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionManageChats), middlewares.HasInput, middlewares.ParseInput},
+		},
+		{
+			Name:        "setchatvar",
+			Description: fmt.Sprintf("Set a placeholder variable of %s", ChatGroup.Name),
+			Handler:     setChatVariable,
+			Arguments:   constants.ChatVariableSetArgs,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionManageChats), middlewares.HasInput, middlewares.ParseInput},
+		},
+		{
+			Name:        "listchatvars",
+			Description: fmt.Sprintf("List placeholder variables of %s", ChatGroup.Name),
+			Handler:     listChatVariables,
+			Arguments:   constants.ChatVariableListArgs,
+			Middlewares: []middlewares.Middleware{middlewares.HasInput, middlewares.ParseInput},
+		},
 		{
 			Name:        LanguageGroup.Add,
 			Description: fmt.Sprintf("Add %s", LanguageGroup.Name),
 			Arguments:   constants.LanguageAddArgs,
 			Handler:     addLanguage,
-			Middlewares: []middlewares.Middleware{middlewares.IsMaster, middlewares.HasInput, middlewares.ParseInput},
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionMaster), middlewares.HasInput, middlewares.ParseInput},
 		},
 		{
 			Name:        LanguageGroup.Remove,
 			Description: fmt.Sprintf("Remove %s", LanguageGroup.Name),
 			Arguments:   constants.LanguageRemoveArgs,
 			Handler:     removeLanguage,
-			Middlewares: []middlewares.Middleware{middlewares.IsMaster, middlewares.HasInput, middlewares.ParseInput},
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionMaster), middlewares.HasInput, middlewares.ParseInput},
 		},
 		{
 			Name:        LanguageGroup.List,
@@ -82,19 +147,26 @@ var (
 			Handler:     listAllLanguages,
 			Middlewares: []middlewares.Middleware{},
 		},
+		{
+			Name:        "setfallback",
+			Description: fmt.Sprintf("Set fallback %s of %s", LanguageGroup.Name, LanguageGroup.Name),
+			Arguments:   constants.LanguageFallbackArgs,
+			Handler:     setFallback,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionMaster), middlewares.HasInput, middlewares.ParseInput},
+		},
 		{
 			Name:        GroupGroup.Add,
 			Description: fmt.Sprintf("Add %s", GroupGroup.Name),
 			Arguments:   constants.GroupAddArgs,
 			Handler:     addGroup,
-			Middlewares: []middlewares.Middleware{middlewares.IsMaster, middlewares.HasInput, middlewares.ParseInput},
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionMaster), middlewares.HasInput, middlewares.ParseInput},
 		},
 		{
 			Name:        GroupGroup.Remove,
 			Description: fmt.Sprintf("Remove %s", GroupGroup.Name),
 			Arguments:   constants.GroupRemoveArgs,
 			Handler:     removeGroup,
-			Middlewares: []middlewares.Middleware{middlewares.IsMaster, middlewares.HasInput, middlewares.ParseInput},
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionMaster), middlewares.HasInput, middlewares.ParseInput},
 		},
 		{
 			Name:        GroupGroup.List,
@@ -115,28 +187,109 @@ var (
 			Description: fmt.Sprintf("Preview messages"),
 			Arguments:   constants.MessageTestArgs,
 			Handler:     testMessages,
-			Middlewares: []middlewares.Middleware{middlewares.HasInput, middlewares.ParseInput},
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionPreview), middlewares.HasInput, middlewares.ParseInput},
 		},
 		{
 			Name:        "sendmessages",
-			Description: fmt.Sprintf("Send messages to a specific group"),
+			Description: fmt.Sprintf("Send messages to groups, languages or chats"),
 			Arguments:   constants.MessageSendArgs,
 			Handler:     sendMessages,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionSend), middlewares.HasInput, middlewares.ParseInput},
+		},
+		{
+			Name:        "schedulemessage",
+			Description: fmt.Sprintf("Schedule messages to a specific group"),
+			Arguments:   constants.ScheduleAddArgs,
+			Handler:     scheduleMessage,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionSend), middlewares.HasInput, middlewares.ParseInput},
+		},
+		{
+			Name:        "listschedules",
+			Description: fmt.Sprintf("List scheduled messages"),
+			Arguments:   constants.ScheduleListArgs,
+			Handler:     listSchedules,
+			Middlewares: []middlewares.Middleware{},
+		},
+		{
+			Name:        "cancelschedule",
+			Description: fmt.Sprintf("Cancel scheduled messages"),
+			Arguments:   constants.ScheduleCancelArgs,
+			Handler:     cancelSchedule,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionSend), middlewares.HasInput, middlewares.ParseInput},
+		},
+		{
+			Name:        "listfailed",
+			Description: fmt.Sprintf("List failed deliveries"),
+			Arguments:   constants.FailedListArgs,
+			Handler:     listFailed,
+			Middlewares: []middlewares.Middleware{},
+		},
+		{
+			Name:        "retryfailed",
+			Description: fmt.Sprintf("Retry failed deliveries of a broadcast"),
+			Arguments:   constants.FailedRetryArgs,
+			Handler:     retryFailed,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionSend), middlewares.HasInput, middlewares.ParseInput},
+		},
+		{
+			Name:        "broadcaststatus",
+			Description: fmt.Sprintf("Show delivery report of a broadcast"),
+			Arguments:   constants.BroadcastStatusArgs,
+			Handler:     broadcastStatus,
 			Middlewares: []middlewares.Middleware{middlewares.HasInput, middlewares.ParseInput},
 		},
+		{
+			Name:        "editbroadcast",
+			Description: fmt.Sprintf("Apply current message text to a sent broadcast"),
+			Arguments:   constants.BroadcastEditArgs,
+			Handler:     editBroadcast,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionSend), middlewares.HasInput, middlewares.ParseInput},
+		},
+		{
+			Name:        "recallbroadcast",
+			Description: fmt.Sprintf("Delete a sent broadcast from every chat"),
+			Arguments:   constants.BroadcastRecallArgs,
+			Handler:     recallBroadcast,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionSend), middlewares.HasInput, middlewares.ParseInput},
+		},
+		{
+			Name:        "listpending",
+			Description: fmt.Sprintf("List broadcasts waiting for an approval"),
+			Arguments:   constants.ApprovalListArgs,
+			Handler:     listPending,
+			Middlewares: []middlewares.Middleware{},
+		},
+		{
+			Name:        "auditlog",
+			Description: fmt.Sprintf("Show the latest actions of admins"),
+			Arguments:   constants.AuditLogArgs,
+			Handler:     auditLog,
+			Middlewares: []middlewares.Middleware{middlewares.RequirePermission(models.PermissionMaster), middlewares.HasInput, middlewares.ParseInput},
+		},
 	}
 )
 
+// Execute runs the command and records it in the audit log,
+// except for runs which only ask for the input.
 func (c *Command) Execute(ctx context.Context) (string, error) {
 	ctx = context.WithValue(ctx, constants.CtxArgsRequired, c.Arguments)
 
+	result, err := c.execute(&ctx)
+	if err != constants.ErrEmptyInput {
+		c.audit(ctx, result, err)
+	}
+
+	return result, err
+}
+
+func (c *Command) execute(ctx *context.Context) (string, error) {
 	for _, middleware := range c.Middlewares {
-		if err := middleware(&ctx); err != nil {
+		if err := middleware(ctx); err != nil {
 			return "", err
 		}
 	}
 
-	result, err := c.Handler(ctx)
+	result, err := c.Handler(*ctx)
 	if err != nil {
 		return "", err
 	}
@@ -144,6 +297,30 @@ func (c *Command) Execute(ctx context.Context) (string, error) {
 	return result, nil
 }
 
+// audit records the parsed arguments of the command, or its raw input
+// if the command failed before the input was parsed.
+func (c *Command) audit(ctx context.Context, result string, err error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxInitiator).(*models.User)
+
+	arguments := make(map[string]string)
+	for _, name := range c.Arguments.Names {
+		switch value := ctx.Value(name).(type) {
+		case nil:
+		case []string:
+			arguments[name] = strings.Join(value, ";")
+		default:
+			arguments[name] = fmt.Sprint(value)
+		}
+	}
+
+	if args, _ := ctx.Value(constants.CtxArgs).([]string); len(arguments) == 0 && len(args) > 0 {
+		arguments[constants.AuditInput] = strings.Join(args, ";")
+	}
+
+	controller.Audit(user.Id, c.Name, arguments, result, err)
+}
+
 var (
 	AdminGroup    = createCommandGroup(constants.CmdAdmin)
 	ChatGroup     = createCommandGroup(constants.CmdChat)
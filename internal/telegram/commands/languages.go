@@ -5,7 +5,9 @@ import (
 	"DC_NewsSender/internal/telegram/controller"
 	"DC_NewsSender/internal/telegram/models"
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"go.uber.org/zap"
@@ -87,11 +89,87 @@ func listAllLanguages(ctx context.Context) (string, error) {
 		return "", err
 	}
 
+	sort.Slice(languages, func(i, j int) bool { return languages[i].Id < languages[j].Id })
+
 	for _, language := range languages {
 		response.WriteString(fmt.Sprintf("\n [%d] %s", language.Id, language.Name))
+
+		if language.FallbackId != nil {
+			if fallback, _ := langService.FindById(*language.FallbackId); fallback != nil {
+				response.WriteString(fmt.Sprintf(" | fallback: [%d] %s", fallback.Id, fallback.Name))
+			}
+		}
 	}
 
 	logger.Debug("Listed all languages")
 
 	return response.String(), nil
 }
+
+func setFallback(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var id uint64 = ctx.Value(constants.LanguageFallbackArgs.Names[0]).(uint64)
+	var fallbackId uint64 = ctx.Value(constants.LanguageFallbackArgs.Names[1]).(uint64)
+
+	var langService = controller.CreateLanguageService()
+
+	logger := controller.Logger.With(
+		zap.String("function", "setFallback"),
+		zap.Int64("userID", user.Id),
+		zap.Uint64("languageID", id),
+		zap.Uint64("fallbackID", fallbackId),
+	)
+
+	logger.Debug("Setting fallback language")
+
+	lang, err := langService.FindById(id)
+	if lang == nil {
+		logger.Error("Failed to find a language", zap.Error(err))
+		return "", errors.New("language not found")
+	}
+
+	// 0 removes the fallback.
+	if fallbackId == 0 {
+		lang.FallbackId = nil
+		if _, err := langService.Update(lang); err != nil {
+			logger.Error("Failed to update language", zap.Error(err))
+			return "", err
+		}
+
+		return fmt.Sprintf("Language %s has no fallback now!", lang.Name), nil
+	}
+
+	fallback, err := langService.FindById(fallbackId)
+	if fallback == nil {
+		logger.Error("Failed to find a fallback language", zap.Error(err))
+		return "", errors.New("fallback language not found")
+	}
+
+	// Walk the chain of the fallback, it must not lead back to the language.
+	visited := map[uint64]bool{}
+	for next := fallback; next != nil && !visited[next.Id]; {
+		if next.Id == lang.Id {
+			return "", fmt.Errorf("fallback [%d] %s leads back to [%d] %s", fallback.Id, fallback.Name, lang.Id, lang.Name)
+		}
+		visited[next.Id] = true
+
+		if next.FallbackId == nil {
+			break
+		}
+		next, _ = langService.FindById(*next.FallbackId)
+	}
+
+	lang.FallbackId = &fallback.Id
+	if _, err := langService.Update(lang); err != nil {
+		logger.Error("Failed to update language", zap.Error(err))
+		return "", err
+	}
+
+	response := fmt.Sprintf("Language %s falls back to %s now!", lang.Name, fallback.Name)
+
+	logger.Debug("Set fallback language", zap.String("response", response))
+
+	return response, nil
+}
@@ -1,13 +1,13 @@
 package commands
 
 import (
-	"DC_NewsSender/internal/telegram/cache"
 	"DC_NewsSender/internal/telegram/constants"
 	"DC_NewsSender/internal/telegram/controller"
 	"DC_NewsSender/internal/telegram/models"
 
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"go.uber.org/zap"
@@ -27,15 +27,16 @@ func listMessages(ctx context.Context) (string, error) {
 	var response strings.Builder
 	response.WriteString("Messages List:")
 
-	var keys []uint64
+	messages, err := controller.CreateMessageService().FindAll()
+	if err != nil {
+		logger.Error("Failed to find all messages", zap.Error(err))
+		return "", err
+	}
 
-	cache.Messages.List.Range(func(key, value interface{}) bool {
-		keys = append(keys, key.(uint64))
-		return true
-	})
+	sort.Slice(messages, func(i, j int) bool { return messages[i].Id < messages[j].Id })
 
-	for _, key := range keys {
-		response.WriteString(fmt.Sprintf("\n [%d]", key))
+	for _, msg := range messages {
+		response.WriteString(fmt.Sprintf("\n [%d]", msg.Id))
 	}
 
 	logger.Debug("Listed all messages", zap.String("response", response.String()))
@@ -56,7 +57,7 @@ func testMessages(ctx context.Context) (string, error) {
 
 	logger.Debug("Testing messages")
 
-	msg := cache.Messages.Find(id)
+	msg, _ := controller.CreateMessageService().FindById(id)
 	if msg == nil {
 		logger.Warn("message not found")
 		return "", constants.ErrNotFound
@@ -65,28 +66,19 @@ func testMessages(ctx context.Context) (string, error) {
 
 	var response string
 	counter := 0
-	switch msg.GetType() {
-	case models.PhotoMessage:
-		for _, text := range msg.Text {
-			if err := controller.SendPhotoByID(user.Id, msg.Photo, text); err != nil {
-				logger.Error("Failed to send message", zap.Error(err))
-				continue
-			}
-			logger.Debug("photo message sent")
-			counter++
+	for languageId := range msg.Text {
+		text, opts, err := controller.RenderSample(msg, languageId)
+		if err != nil {
+			logger.Error("Failed to render message", zap.Error(err))
+			continue
 		}
-	case models.TextMessage:
-		for _, text := range msg.Text {
-			if err := controller.SendText(user.Id, text); err != nil {
-				logger.Error("Failed to send message", zap.Error(err))
-				continue
-			}
-			logger.Debug("text message sent")
-			counter++
+
+		if _, err := controller.SendMessage(user.Id, msg.MediaFor(languageId), text, opts); err != nil {
+			logger.Error("Failed to send message", zap.Error(err))
+			continue
 		}
-	default:
-		logger.Warn("unknown message type")
-		return "unknown message type", nil
+		logger.Debug("message sent")
+		counter++
 	}
 
 	response = fmt.Sprintf("%d messages sent.", counter)
@@ -101,10 +93,7 @@ func sendMessages(ctx context.Context) (string, error) {
 	var user = ctx.Value(constants.CtxUser).(*models.User)
 
 	var msgId uint64 = ctx.Value(constants.MessageSendArgs.Names[0]).(uint64)
-	var groupId uint64 = ctx.Value(constants.MessageSendArgs.Names[1]).(uint64)
-
-	var groupService = controller.CreateGroupService()
-	var chatService = controller.CreateChatService()
+	var terms []string = ctx.Value(constants.MessageSendArgs.Names[1]).([]string)
 
 	logger := controller.Logger.With(
 		zap.String("function", "sendMessages"),
@@ -113,63 +102,23 @@ func sendMessages(ctx context.Context) (string, error) {
 
 	logger.Debug("Sending message")
 
-	group, err := groupService.FindById(groupId)
+	target, err := models.ParseTarget(terms)
 	if err != nil {
+		logger.Error("Failed to parse target", zap.Error(err))
 		return "", err
 	}
-	logger.Debug("Group found", zap.Any("group", group))
 
-	msg := cache.Messages.Find(msgId)
-	logger.Debug("Message found", zap.Any("msg", msg))
-	chats, err := chatService.FindBy("group_id", fmt.Sprint(group.Id))
-	if err != nil {
-		return "", err
+	if !user.CanTarget(models.PermissionSend, target) {
+		logger.Warn("target not permitted", zap.String("target", target.String()))
+		return "", constants.ErrNoPermission
 	}
-	logger.Debug("Chats found", zap.Any("chats", chats))
-
-	counter := 0
-	failed := []string{}
 
-	for _, chat := range chats {
-		if msg.Text[chat.LanguageId] == "" || !chat.IsActive {
-			continue
-		}
-
-		if chat.Group.Id == group.Id {
-			switch msg.GetType() {
-			case models.PhotoMessage:
-				if err := controller.SendPhotoByID(chat.Id, msg.Photo, msg.Text[chat.LanguageId]); err != nil {
-					logger.Error("Failed to send message", zap.Any("chat", chat), zap.Error(err))
-					failed = append(failed, chat.Name)
-					continue
-				}
-				counter++
-				continue
-			case models.TextMessage:
-				if err := controller.SendText(chat.Id, msg.Text[chat.LanguageId]); err != nil {
-					logger.Error("Failed to send message", zap.Any("chat", chat), zap.Error(err))
-					failed = append(failed, chat.Name)
-					continue
-				}
-				counter++
-				continue
-			default:
-				return "Error: unknown message type", nil
-			}
-		}
-	}
-
-	var response string
-	if counter > 0 {
-		response = fmt.Sprintf("%d messages sent to group [%d] %s.", counter, group.Id, group.Name)
-	} else {
-		response = fmt.Sprintf("No messages sent to group [%d] %s.", group.Id, group.Name)
-	}
-	if len(failed) > 0 {
-		response += fmt.Sprintf("\nFailed to send to: %s", strings.Join(failed, ", "))
+	if err := controller.RequestBroadcast(user.Id, msgId, target); err != nil {
+		logger.Error("Failed to request broadcast", zap.Error(err))
+		return "", err
 	}
 
-	logger.Debug("Sent message")
+	logger.Debug("Requested broadcast")
 
-	return response, nil
+	return "", nil
 }
@@ -4,7 +4,6 @@ import (
 	"DC_NewsSender/internal/telegram/constants"
 	"DC_NewsSender/internal/telegram/models"
 	"context"
-	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -12,14 +11,18 @@ import (
 
 type Middleware func(ctx *context.Context) error
 
-func IsMaster(ctx *context.Context) error {
-	var user = (*ctx).Value(constants.CtxInitiator).(*models.User)
+// RequirePermission lets only users with the permission in at least one group run the command,
+// commands about groups check the permission in these groups on their own.
+func RequirePermission(permission models.Permission) Middleware {
+	return func(ctx *context.Context) error {
+		var user = (*ctx).Value(constants.CtxInitiator).(*models.User)
 
-	if !user.IsMaster {
-		return errors.New("not enough privileges")
-	}
+		if !user.Can(permission) {
+			return constants.ErrNoPermission
+		}
 
-	return nil
+		return nil
+	}
 }
 
 func ParseInput(ctx *context.Context) error {
@@ -29,7 +32,20 @@ func ParseInput(ctx *context.Context) error {
 	var argNames = argsRequired.Names
 	var argTypes = argsRequired.Types
 
-	if len(args) != len(argNames) || len(args) != len(argTypes) {
+	if len(argNames) != len(argTypes) {
+		return constants.ErrInvalidInput
+	}
+
+	if argsRequired.Variadic {
+		if len(argNames) == 0 || len(args) < len(argNames) {
+			return constants.ErrInvalidInput
+		}
+
+		last := len(argNames) - 1
+		*ctx = context.WithValue(*ctx, argNames[last], args[last:])
+
+		args, argNames = args[:last], argNames[:last]
+	} else if len(args) < argsRequired.Required() || len(args) > len(argNames) {
 		return constants.ErrInvalidInput
 	}
 
@@ -61,7 +77,7 @@ func HasInput(ctx *context.Context) error {
 	var args = (*ctx).Value(constants.CtxArgs).([]string)
 	var argsRequired = (*ctx).Value(constants.CtxArgsRequired).(models.Arguments)
 
-	if args == nil || len(args) < len(argsRequired.Names) {
+	if len(args) < argsRequired.Required() {
 		return constants.ErrEmptyInput
 	}
 
@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/controller"
+	"DC_NewsSender/internal/telegram/models"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func scheduleMessage(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var msgId uint64 = ctx.Value(constants.ScheduleAddArgs.Names[0]).(uint64)
+	var groupId uint64 = ctx.Value(constants.ScheduleAddArgs.Names[1]).(uint64)
+	var sendAtInput string = ctx.Value(constants.ScheduleAddArgs.Names[2]).(string)
+
+	logger := controller.Logger.With(
+		zap.String("function", "scheduleMessage"),
+		zap.Int64("userID", user.Id),
+	)
+
+	logger.Debug("Scheduling message")
+
+	sendAt, err := time.ParseInLocation(constants.ScheduleTimeLayout, strings.TrimSpace(sendAtInput), time.Local)
+	if err != nil {
+		logger.Error("Failed to parse send time", zap.Error(err))
+		return "", fmt.Errorf("invalid send_at, use %s", constants.ScheduleTimeLayout)
+	}
+
+	if !sendAt.After(time.Now()) {
+		return "", errors.New("send_at must be in the future")
+	}
+
+	if msg, err := controller.CreateMessageService().FindById(msgId); msg == nil {
+		logger.Error("Failed to find a message", zap.Error(err))
+		return "", errors.New("message not found")
+	}
+
+	group, err := controller.CreateGroupService().FindById(groupId)
+	if group == nil {
+		logger.Error("Failed to find a group", zap.Error(err))
+		return "", errors.New("group not found")
+	}
+
+	if !user.CanIn(models.PermissionSend, group.Id) {
+		logger.Warn("group not permitted", zap.Uint64("groupID", group.Id))
+		return "", constants.ErrNoPermission
+	}
+
+	schedule, err := controller.CreateScheduleService().Add(&models.Schedule{
+		MessageId:   msgId,
+		GroupId:     groupId,
+		InitiatorId: user.Id,
+		SendAt:      sendAt,
+		Status:      models.ScheduleStatusPending,
+	})
+	if err != nil {
+		logger.Error("Failed to add a schedule", zap.Error(err))
+		return "", err
+	}
+
+	result := fmt.Sprintf("Schedule [%d] created: message [%d] to group [%d] %s at %s.",
+		schedule.Id, msgId, group.Id, group.Name, sendAt.Format(constants.ScheduleTimeLayout))
+	logger.Debug("Scheduled message", zap.String("result", result))
+
+	return result, nil
+}
+
+func listSchedules(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	logger := controller.Logger.With(
+		zap.String("function", "listSchedules"),
+		zap.Int64("userID", user.Id),
+	)
+
+	logger.Debug("Listing all schedules")
+
+	schedules, err := controller.CreateScheduleService().FindAll()
+	if err != nil {
+		logger.Error("Failed to find all schedules", zap.Error(err))
+		return "", err
+	}
+
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].SendAt.Before(schedules[j].SendAt) })
+
+	var response strings.Builder
+	response.WriteString("Schedule List:")
+
+	for _, schedule := range schedules {
+		if schedule.Status != models.ScheduleStatusPending {
+			continue
+		}
+		response.WriteString(fmt.Sprintf("\n [%d] message [%d] to group [%d] at %s",
+			schedule.Id, schedule.MessageId, schedule.GroupId, schedule.SendAt.Format(constants.ScheduleTimeLayout)))
+	}
+
+	logger.Debug("Listed all schedules", zap.String("response", response.String()))
+
+	return response.String(), nil
+}
+
+func cancelSchedule(ctx context.Context) (string, error) {
+	var controller = ctx.Value(constants.CtxController).(*controller.Controller)
+	var user = ctx.Value(constants.CtxUser).(*models.User)
+
+	var id uint64 = ctx.Value(constants.ScheduleCancelArgs.Names[0]).(uint64)
+
+	var scheduleService = controller.CreateScheduleService()
+
+	logger := controller.Logger.With(
+		zap.String("function", "cancelSchedule"),
+		zap.Int64("userID", user.Id),
+	)
+
+	logger.Debug("Cancelling schedule")
+
+	schedule, _ := scheduleService.FindById(id)
+	if schedule == nil || schedule.Status != models.ScheduleStatusPending {
+		err := constants.ErrNotFound
+		logger.Error("Failed to cancel a schedule", zap.Error(err))
+		return "", err
+	}
+
+	// Only the initiator of the schedule or a master cancels it.
+	if !user.IsMaster && (schedule.InitiatorId != user.Id || !user.CanIn(models.PermissionSend, schedule.GroupId)) {
+		logger.Warn("schedule not permitted", zap.Int64("initiatorID", schedule.InitiatorId), zap.Uint64("groupID", schedule.GroupId))
+		return "", constants.ErrNoPermission
+	}
+
+	schedule.Status = models.ScheduleStatusCancelled
+	if _, err := scheduleService.Update(schedule); err != nil {
+		logger.Error("Failed to cancel a schedule", zap.Error(err))
+		return "", err
+	}
+
+	result := fmt.Sprintf("Schedule %d has been cancelled!", id)
+
+	logger.Debug("Cancelled schedule")
+
+	return result, nil
+}
@@ -0,0 +1,7 @@
+package constants
+
+import "time"
+
+// AlbumCollectDelay is how long the bot waits for the remaining items of an album.
+// Telegram delivers every album item as a separate update.
+const AlbumCollectDelay time.Duration = time.Second
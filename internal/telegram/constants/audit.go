@@ -0,0 +1,17 @@
+package constants
+
+const (
+	// AuditInput is the argument the raw input of a command is recorded as
+	// if the command failed before the input was parsed.
+	AuditInput string = "input"
+	// AuditStash is the command stashes of messages are recorded as.
+	AuditStash string = "stash"
+	// AuditResultLimit is how many characters of a result or an error the audit log keeps.
+	AuditResultLimit int = 500
+	// AuditLogDefault is how many entries /auditlog shows if no count is given.
+	AuditLogDefault int = 10
+	// AuditLogMax is the most entries /auditlog shows at once.
+	AuditLogMax int = 50
+	// AuditLogResultLimit is how many characters of a result /auditlog shows.
+	AuditLogResultLimit int = 80
+)
@@ -0,0 +1,15 @@
+package constants
+
+import "time"
+
+const (
+	// BroadcastRequestTTL is how long a requested broadcast can be confirmed.
+	BroadcastRequestTTL time.Duration = time.Hour
+	// ApprovalTTL is how long a broadcast waits for an approval unless APPROVAL_TTL is set.
+	ApprovalTTL time.Duration = 24 * time.Hour
+	// PreviewChatLimit is how many chats the confirmation of a broadcast lists.
+	PreviewChatLimit int = 30
+	// PreviewTextLimit is how long the confirmation of a broadcast may grow,
+	// Telegram rejects texts over 4096 characters and the confirmation hint follows the preview.
+	PreviewTextLimit int = 4000
+)
@@ -0,0 +1,17 @@
+package constants
+
+// Unique ids of inline buttons, their payload is passed as callback data.
+const (
+	// CallbackChatLanguage picks the language of a pending chat, payload is "chat_id:language_id".
+	CallbackChatLanguage string = "chat_language"
+	// CallbackChatGroup picks the group of a pending chat, payload is "chat_id:group_id".
+	CallbackChatGroup string = "chat_group"
+	// CallbackBroadcastConfirm starts a requested broadcast, payload is the request id.
+	CallbackBroadcastConfirm string = "broadcast_confirm"
+	// CallbackBroadcastCancel drops a requested broadcast, payload is the request id.
+	CallbackBroadcastCancel string = "broadcast_cancel"
+	// CallbackBroadcastApprove starts a broadcast waiting for an approval, payload is the approval id.
+	CallbackBroadcastApprove string = "broadcast_approve"
+	// CallbackBroadcastReject drops a broadcast waiting for an approval, payload is the approval id.
+	CallbackBroadcastReject string = "broadcast_reject"
+)
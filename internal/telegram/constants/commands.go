@@ -31,6 +31,14 @@ var (
 		Names: []string{},
 		Types: []reflect.Kind{},
 	}
+	RoleGrantArgs models.Arguments = models.Arguments{
+		Names: []string{"user_id", "role", "group_id"},
+		Types: []reflect.Kind{reflect.Int64, reflect.String, reflect.Uint64},
+	}
+	RoleRevokeArgs models.Arguments = models.Arguments{
+		Names: []string{"user_id", "role", "group_id"},
+		Types: []reflect.Kind{reflect.Int64, reflect.String, reflect.Uint64},
+	}
 
 	ChatAddArgs models.Arguments = models.Arguments{
 		Names: []string{"chat_id", "chat_name", "language_id", "group_id"},
@@ -44,6 +52,34 @@ var (
 		Names: []string{},
 		Types: []reflect.Kind{},
 	}
+	ChatActivateArgs models.Arguments = models.Arguments{
+		Names: []string{"chat_id"},
+		Types: []reflect.Kind{reflect.Int64},
+	}
+	ChatDeactivateArgs models.Arguments = models.Arguments{
+		Names: []string{"chat_id"},
+		Types: []reflect.Kind{reflect.Int64},
+	}
+	ChatEditArgs models.Arguments = models.Arguments{
+		Names: []string{"chat_id", "field", "value"},
+		Types: []reflect.Kind{reflect.Int64, reflect.String, reflect.String},
+	}
+	ChatAddToGroupArgs models.Arguments = models.Arguments{
+		Names: []string{"chat_id", "group_id"},
+		Types: []reflect.Kind{reflect.Int64, reflect.Uint64},
+	}
+	ChatRemoveFromGroupArgs models.Arguments = models.Arguments{
+		Names: []string{"chat_id", "group_id"},
+		Types: []reflect.Kind{reflect.Int64, reflect.Uint64},
+	}
+	ChatVariableSetArgs models.Arguments = models.Arguments{
+		Names: []string{"chat_id", "key", "value"},
+		Types: []reflect.Kind{reflect.Int64, reflect.String, reflect.String},
+	}
+	ChatVariableListArgs models.Arguments = models.Arguments{
+		Names: []string{"chat_id"},
+		Types: []reflect.Kind{reflect.Int64},
+	}
 
 	LanguageAddArgs models.Arguments = models.Arguments{
 		Names: []string{"language_name"},
@@ -57,6 +93,10 @@ var (
 		Names: []string{},
 		Types: []reflect.Kind{},
 	}
+	LanguageFallbackArgs models.Arguments = models.Arguments{
+		Names: []string{"language_id", "fallback_id"},
+		Types: []reflect.Kind{reflect.Uint64, reflect.Uint64},
+	}
 
 	GroupAddArgs models.Arguments = models.Arguments{
 		Names: []string{"group_name"},
@@ -80,7 +120,54 @@ var (
 		Types: []reflect.Kind{},
 	}
 	MessageSendArgs models.Arguments = models.Arguments{
-		Names: []string{"message_id", "group_id"},
-		Types: []reflect.Kind{reflect.Uint64, reflect.Uint64},
+		Names:    []string{"message_id", "target"},
+		Types:    []reflect.Kind{reflect.Uint64, reflect.String},
+		Variadic: true,
+	}
+
+	ScheduleAddArgs models.Arguments = models.Arguments{
+		Names: []string{"message_id", "group_id", "send_at"},
+		Types: []reflect.Kind{reflect.Uint64, reflect.Uint64, reflect.String},
+	}
+	ScheduleCancelArgs models.Arguments = models.Arguments{
+		Names: []string{"schedule_id"},
+		Types: []reflect.Kind{reflect.Uint64},
+	}
+	ScheduleListArgs models.Arguments = models.Arguments{
+		Names: []string{},
+		Types: []reflect.Kind{},
+	}
+
+	FailedListArgs models.Arguments = models.Arguments{
+		Names: []string{},
+		Types: []reflect.Kind{},
+	}
+	FailedRetryArgs models.Arguments = models.Arguments{
+		Names: []string{"job_id"},
+		Types: []reflect.Kind{reflect.Uint64},
+	}
+
+	BroadcastStatusArgs models.Arguments = models.Arguments{
+		Names: []string{"job_id"},
+		Types: []reflect.Kind{reflect.Uint64},
+	}
+
+	BroadcastEditArgs models.Arguments = models.Arguments{
+		Names: []string{"job_id"},
+		Types: []reflect.Kind{reflect.Uint64},
+	}
+
+	BroadcastRecallArgs models.Arguments = models.Arguments{
+		Names: []string{"job_id"},
+		Types: []reflect.Kind{reflect.Uint64},
+	}
+	ApprovalListArgs models.Arguments = models.Arguments{
+		Names: []string{},
+		Types: []reflect.Kind{},
+	}
+	AuditLogArgs models.Arguments = models.Arguments{
+		Names:    []string{"count", "user_id"},
+		Types:    []reflect.Kind{reflect.Uint64, reflect.Int64},
+		Optional: 2,
 	}
 )
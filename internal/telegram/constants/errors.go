@@ -6,3 +6,4 @@ var ErrInvalidInput = errors.New("invalid input")
 var ErrNotFound = errors.New("not found")
 var ErrAlreadyExists = errors.New("already exists")
 var ErrEmptyInput = errors.New("empty input")
+var ErrNoPermission = errors.New("not enough privileges")
@@ -0,0 +1,13 @@
+package constants
+
+import "time"
+
+const (
+	// ScheduleTimeLayout is the layout admins use to enter a schedule time.
+	ScheduleTimeLayout string = "2006-01-02T15:04"
+	// ScheduleInterval is how often pending schedules are checked.
+	ScheduleInterval time.Duration = 30 * time.Second
+	// ScheduleMissedGrace is how late a schedule may still fire after downtime.
+	// Older schedules are marked as missed instead.
+	ScheduleMissedGrace time.Duration = time.Hour
+)
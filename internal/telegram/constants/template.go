@@ -0,0 +1,4 @@
+package constants
+
+// TemplateDateLayout is the layout of {{date}} in message texts without a layout of its own.
+const TemplateDateLayout string = "2006-01-02"
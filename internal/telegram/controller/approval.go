@@ -0,0 +1,218 @@
+package controller
+
+import (
+	db_models "DC_NewsSender/internal/db/models"
+	"DC_NewsSender/internal/db/repositories"
+	"DC_NewsSender/internal/telegram/cache"
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/models"
+
+	"go.uber.org/zap"
+)
+
+type ApprovalService struct {
+	cache  cache.ICache[uint64, models.Approval]
+	logger *zap.Logger
+	repo   repositories.IRepository[db_models.Approval, uint64]
+}
+
+func (s *ApprovalService) ClearCache() {
+	logger := s.logger.With(
+		zap.String("function", "ClearCache"),
+	)
+
+	logger.Debug("Clearing cache")
+
+	s.cache.Clear()
+
+	logger.Debug("Cache cleared")
+}
+
+func (s *ApprovalService) UpdateCache() error {
+	logger := s.logger.With(
+		zap.String("function", "UpdateCache"),
+	)
+
+	logger.Debug("Updating cache")
+
+	s.ClearCache()
+	results, err := s.findAllFromDb()
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		s.cache.Add(result.Id, result)
+	}
+
+	logger.Debug("Cache updated")
+
+	return nil
+}
+
+func (s *ApprovalService) FindBy(selector string, values ...string) ([]models.Approval, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindBy"),
+		zap.String("selector", selector),
+		zap.Strings("values", values),
+	)
+
+	logger.Debug("Finding approval")
+
+	dbResults, err := s.repo.FindBy(selector, values...)
+	if err != nil {
+		logger.Error("Failed to find approval in db", zap.Error(err))
+		return nil, err
+	}
+
+	var result []models.Approval
+
+	for _, dbRes := range *dbResults {
+		result = append(result, models.Approval(dbRes))
+	}
+
+	logger.Debug("Found approval in db", zap.Any("approval", result))
+
+	return result, nil
+}
+
+func (s *ApprovalService) FindByName(name string) (*models.Approval, error) {
+	panic("not implemented")
+}
+
+func (s *ApprovalService) FindById(id uint64) (*models.Approval, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindById"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Finding approval")
+
+	if result := s.cache.Find(id); result != nil {
+		logger.Debug("Found approval in cache", zap.Any("approval", result))
+		return result, nil
+	}
+
+	dbResult, err := s.repo.FindById(id)
+	if err != nil {
+		logger.Error("Failed to find approval in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found approval in db", zap.Any("approval", dbResult))
+
+	s.cache.Add(dbResult.Id, models.Approval(*dbResult))
+
+	return s.cache.Find(id), nil
+}
+
+func (s *ApprovalService) Add(approval *models.Approval) (*models.Approval, error) {
+	logger := s.logger.With(
+		zap.String("function", "Add"),
+		zap.Any("approval", approval),
+	)
+
+	logger.Debug("Adding approval")
+
+	dbApproval := db_models.Approval(*approval)
+	dbResult, err := s.repo.Add(&dbApproval)
+	if err != nil {
+		logger.Error("Failed to add approval", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Added approval", zap.Any("result", dbResult))
+
+	result := models.Approval(*dbResult)
+
+	s.cache.Add(dbResult.Id, result)
+
+	return &result, nil
+}
+
+func (s *ApprovalService) Update(approval *models.Approval) (*models.Approval, error) {
+	logger := s.logger.With(
+		zap.String("function", "Update"),
+		zap.Any("approval", approval),
+	)
+
+	logger.Debug("Updating approval")
+
+	dbApproval := db_models.Approval(*approval)
+	result, err := s.repo.Update(&dbApproval)
+	if err != nil {
+		logger.Error("Failed to update approval", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Updated approval", zap.Any("result", result))
+
+	s.cache.Add(approval.Id, *approval)
+
+	return approval, nil
+}
+
+func (s *ApprovalService) Remove(id uint64) error {
+	logger := s.logger.With(
+		zap.String("function", "Remove"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Removing approval")
+
+	approvalToDelete, _ := s.FindById(id)
+	if approvalToDelete == nil {
+		err := constants.ErrNotFound
+		logger.Error("Failed to remove approval", zap.Error(err))
+		return err
+	}
+
+	if err := s.repo.Remove(approvalToDelete.Id); err != nil {
+		logger.Error("Failed to remove approval", zap.Error(err))
+		return err
+	}
+
+	logger.Debug("Removed approval")
+
+	s.cache.Remove(approvalToDelete.Id)
+
+	return nil
+}
+
+func (s *ApprovalService) FindAll() ([]models.Approval, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindAll"),
+	)
+
+	logger.Debug("Finding approvals")
+
+	result := s.cache.FindAll()
+
+	logger.Debug("Found approvals in cache")
+
+	return result, nil
+}
+
+func (s *ApprovalService) findAllFromDb() ([]models.Approval, error) {
+	logger := s.logger.With(
+		zap.String("function", "findAllFromDb"),
+	)
+
+	logger.Debug("Finding approvals")
+
+	dbResults, err := s.repo.FindAll()
+	if err != nil {
+		logger.Error("Failed to find approvals in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found approvals in db", zap.Any("approvals", dbResults))
+
+	result := make([]models.Approval, 0, len(*dbResults))
+
+	for _, approval := range *dbResults {
+		result = append(result, models.Approval(approval))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,227 @@
+package controller
+
+import (
+	db_models "DC_NewsSender/internal/db/models"
+	"DC_NewsSender/internal/db/repositories"
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/models"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AuditService is not cached: the audit log only grows and is read rarely.
+type AuditService struct {
+	logger *zap.Logger
+	repo   repositories.IRepository[db_models.AuditEntry, uint64]
+}
+
+func (s *AuditService) ClearCache() {}
+
+func (s *AuditService) UpdateCache() error {
+	return nil
+}
+
+func (s *AuditService) FindBy(selector string, values ...string) ([]models.AuditEntry, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindBy"),
+		zap.String("selector", selector),
+		zap.Strings("values", values),
+	)
+
+	logger.Debug("Finding audit entry")
+
+	dbResults, err := s.repo.FindBy(selector, values...)
+	if err != nil {
+		logger.Error("Failed to find audit entry in db", zap.Error(err))
+		return nil, err
+	}
+
+	var result []models.AuditEntry
+
+	for _, dbRes := range *dbResults {
+		result = append(result, models.AuditEntry(dbRes))
+	}
+
+	logger.Debug("Found audit entry in db", zap.Any("entry", result))
+
+	return result, nil
+}
+
+func (s *AuditService) FindByName(name string) (*models.AuditEntry, error) {
+	panic("not implemented")
+}
+
+func (s *AuditService) FindById(id uint64) (*models.AuditEntry, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindById"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Finding audit entry")
+
+	dbResult, err := s.repo.FindById(id)
+	if err != nil {
+		logger.Error("Failed to find audit entry in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found audit entry in db", zap.Any("entry", dbResult))
+
+	result := models.AuditEntry(*dbResult)
+
+	return &result, nil
+}
+
+func (s *AuditService) Add(entry *models.AuditEntry) (*models.AuditEntry, error) {
+	logger := s.logger.With(
+		zap.String("function", "Add"),
+		zap.Any("entry", entry),
+	)
+
+	logger.Debug("Adding audit entry")
+
+	dbEntry := db_models.AuditEntry(*entry)
+	dbResult, err := s.repo.Add(&dbEntry)
+	if err != nil {
+		logger.Error("Failed to add audit entry", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Added audit entry", zap.Any("result", dbResult))
+
+	result := models.AuditEntry(*dbResult)
+
+	return &result, nil
+}
+
+func (s *AuditService) Update(entry *models.AuditEntry) (*models.AuditEntry, error) {
+	logger := s.logger.With(
+		zap.String("function", "Update"),
+		zap.Any("entry", entry),
+	)
+
+	logger.Debug("Updating audit entry")
+
+	dbEntry := db_models.AuditEntry(*entry)
+	result, err := s.repo.Update(&dbEntry)
+	if err != nil {
+		logger.Error("Failed to update audit entry", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Updated audit entry", zap.Any("result", result))
+
+	return entry, nil
+}
+
+func (s *AuditService) Remove(id uint64) error {
+	logger := s.logger.With(
+		zap.String("function", "Remove"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Removing audit entry")
+
+	entryToDelete, _ := s.FindById(id)
+	if entryToDelete == nil {
+		err := constants.ErrNotFound
+		logger.Error("Failed to remove audit entry", zap.Error(err))
+		return err
+	}
+
+	if err := s.repo.Remove(entryToDelete.Id); err != nil {
+		logger.Error("Failed to remove audit entry", zap.Error(err))
+		return err
+	}
+
+	logger.Debug("Removed audit entry")
+
+	return nil
+}
+
+func (s *AuditService) FindAll() ([]models.AuditEntry, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindAll"),
+	)
+
+	logger.Debug("Finding audit entries")
+
+	dbResults, err := s.repo.FindAll()
+	if err != nil {
+		logger.Error("Failed to find audit entries in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found audit entries in db")
+
+	result := make([]models.AuditEntry, 0, len(*dbResults))
+
+	for _, entry := range *dbResults {
+		result = append(result, models.AuditEntry(entry))
+	}
+
+	return result, nil
+}
+
+// FindLatest finds at most limit entries, the latest first, of the initiator or of everyone if initiatorId is 0.
+func (s *AuditService) FindLatest(limit int, initiatorId int64) ([]models.AuditEntry, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindLatest"),
+		zap.Int("limit", limit),
+		zap.Int64("initiatorID", initiatorId),
+	)
+
+	logger.Debug("Finding latest audit entries")
+
+	var dbResults *[]db_models.AuditEntry
+	var err error
+	if initiatorId != 0 {
+		dbResults, err = s.repo.FindLatest(limit, "initiator_id = ?", fmt.Sprint(initiatorId))
+	} else {
+		dbResults, err = s.repo.FindLatest(limit, "")
+	}
+	if err != nil {
+		logger.Error("Failed to find latest audit entries in db", zap.Error(err))
+		return nil, err
+	}
+
+	result := make([]models.AuditEntry, 0, len(*dbResults))
+
+	for _, entry := range *dbResults {
+		result = append(result, models.AuditEntry(entry))
+	}
+
+	return result, nil
+}
+
+// Audit records an action of the initiator, a failure to record it is only logged.
+func (c *Controller) Audit(initiatorId int64, command string, arguments map[string]string, result string, err error) {
+	entry := &models.AuditEntry{
+		InitiatorId: initiatorId,
+		Command:     command,
+		Arguments:   arguments,
+		Result:      truncate(result, constants.AuditResultLimit),
+		CreatedAt:   time.Now(),
+	}
+	if err != nil {
+		entry.Error = truncate(err.Error(), constants.AuditResultLimit)
+	}
+
+	c.auditService().Add(entry)
+}
+
+// AuditLog returns at most limit entries, the latest first, of the initiator or of everyone if initiatorId is 0.
+func (c *Controller) AuditLog(limit int, initiatorId int64) ([]models.AuditEntry, error) {
+	return c.auditService().FindLatest(limit, initiatorId)
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+
+	return string(runes[:limit]) + "..."
+}
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	db_models "DC_NewsSender/internal/db/models"
+	"DC_NewsSender/internal/db/repositories"
+	"DC_NewsSender/internal/telegram/cache"
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/models"
+
+	"go.uber.org/zap"
+)
+
+type BroadcastService struct {
+	cache  cache.ICache[uint64, models.Broadcast]
+	logger *zap.Logger
+	repo   repositories.IRepository[db_models.Broadcast, uint64]
+}
+
+func (s *BroadcastService) ClearCache() {
+	logger := s.logger.With(
+		zap.String("function", "ClearCache"),
+	)
+
+	logger.Debug("Clearing cache")
+
+	s.cache.Clear()
+
+	logger.Debug("Cache cleared")
+}
+
+func (s *BroadcastService) UpdateCache() error {
+	logger := s.logger.With(
+		zap.String("function", "UpdateCache"),
+	)
+
+	logger.Debug("Updating cache")
+
+	s.ClearCache()
+	results, err := s.findAllFromDb()
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		s.cache.Add(result.Id, result)
+	}
+
+	logger.Debug("Cache updated")
+
+	return nil
+}
+
+func (s *BroadcastService) FindBy(selector string, values ...string) ([]models.Broadcast, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindBy"),
+		zap.String("selector", selector),
+		zap.Strings("values", values),
+	)
+
+	logger.Debug("Finding broadcast")
+
+	dbResults, err := s.repo.FindBy(selector, values...)
+	if err != nil {
+		logger.Error("Failed to find broadcast in db", zap.Error(err))
+		return nil, err
+	}
+
+	var result []models.Broadcast
+
+	for _, dbRes := range *dbResults {
+		result = append(result, models.Broadcast(dbRes))
+	}
+
+	logger.Debug("Found broadcast in db", zap.Any("broadcast", result))
+
+	return result, nil
+}
+
+func (s *BroadcastService) FindByName(name string) (*models.Broadcast, error) {
+	panic("not implemented")
+}
+
+func (s *BroadcastService) FindById(id uint64) (*models.Broadcast, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindById"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Finding broadcast")
+
+	if result := s.cache.Find(id); result != nil {
+		logger.Debug("Found broadcast in cache", zap.Any("broadcast", result))
+		return result, nil
+	}
+
+	dbResult, err := s.repo.FindById(id)
+	if err != nil {
+		logger.Error("Failed to find broadcast in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found broadcast in db", zap.Any("broadcast", dbResult))
+
+	s.cache.Add(dbResult.Id, models.Broadcast(*dbResult))
+
+	return s.cache.Find(id), nil
+}
+
+func (s *BroadcastService) Add(broadcast *models.Broadcast) (*models.Broadcast, error) {
+	logger := s.logger.With(
+		zap.String("function", "Add"),
+		zap.Any("broadcast", broadcast),
+	)
+
+	logger.Debug("Adding broadcast")
+
+	dbBroadcast := db_models.Broadcast(*broadcast)
+	dbResult, err := s.repo.Add(&dbBroadcast)
+	if err != nil {
+		logger.Error("Failed to add broadcast", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Added broadcast", zap.Any("result", dbResult))
+
+	result := models.Broadcast(*dbResult)
+
+	s.cache.Add(dbResult.Id, result)
+
+	return &result, nil
+}
+
+func (s *BroadcastService) Update(broadcast *models.Broadcast) (*models.Broadcast, error) {
+	logger := s.logger.With(
+		zap.String("function", "Update"),
+		zap.Any("broadcast", broadcast),
+	)
+
+	logger.Debug("Updating broadcast")
+
+	dbBroadcast := db_models.Broadcast(*broadcast)
+	result, err := s.repo.Update(&dbBroadcast)
+	if err != nil {
+		logger.Error("Failed to update broadcast", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Updated broadcast", zap.Any("result", result))
+
+	s.cache.Add(broadcast.Id, *broadcast)
+
+	return broadcast, nil
+}
+
+func (s *BroadcastService) Remove(id uint64) error {
+	logger := s.logger.With(
+		zap.String("function", "Remove"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Removing broadcast")
+
+	broadcastToDelete, _ := s.FindById(id)
+	if broadcastToDelete == nil {
+		err := constants.ErrNotFound
+		logger.Error("Failed to remove broadcast", zap.Error(err))
+		return err
+	}
+
+	if err := s.repo.Remove(broadcastToDelete.Id); err != nil {
+		logger.Error("Failed to remove broadcast", zap.Error(err))
+		return err
+	}
+
+	logger.Debug("Removed broadcast")
+
+	s.cache.Remove(broadcastToDelete.Id)
+
+	return nil
+}
+
+func (s *BroadcastService) FindAll() ([]models.Broadcast, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindAll"),
+	)
+
+	logger.Debug("Finding broadcasts")
+
+	result := s.cache.FindAll()
+
+	logger.Debug("Found broadcasts in cache")
+
+	return result, nil
+}
+
+func (s *BroadcastService) findAllFromDb() ([]models.Broadcast, error) {
+	logger := s.logger.With(
+		zap.String("function", "findAllFromDb"),
+	)
+
+	logger.Debug("Finding broadcasts")
+
+	dbResults, err := s.repo.FindAll()
+	if err != nil {
+		logger.Error("Failed to find broadcasts in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found broadcasts in db", zap.Any("broadcasts", dbResults))
+
+	result := make([]models.Broadcast, 0, len(*dbResults))
+
+	for _, broadcast := range *dbResults {
+		result = append(result, models.Broadcast(broadcast))
+	}
+
+	return result, nil
+}
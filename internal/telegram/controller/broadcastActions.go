@@ -0,0 +1,245 @@
+package controller
+
+import (
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/delivery"
+	"DC_NewsSender/internal/telegram/models"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	tele "gopkg.in/telebot.v3"
+)
+
+// EditBroadcast re-applies the current text or caption of the message
+// to every delivered copy of the broadcast.
+func (c *Controller) EditBroadcast(initiatorId int64, broadcastId uint64) (string, error) {
+	logger := c.Logger.With(
+		zap.String("function", "EditBroadcast"),
+		zap.Int64("initiatorID", initiatorId),
+		zap.Uint64("broadcastID", broadcastId),
+	)
+
+	logger.Debug("Editing broadcast")
+
+	broadcast, records, err := c.findDelivered(broadcastId)
+	if err != nil {
+		logger.Error("Failed to find delivered messages", zap.Error(err))
+		return "", err
+	}
+
+	msg, _ := c.CreateMessageService().FindById(broadcast.MessageId)
+	if msg == nil {
+		logger.Warn("message not found")
+		return "", errors.New("message not found")
+	}
+
+	var chatService = c.CreateChatService()
+
+	task := func(record models.Delivery) delivery.Task {
+		// Only the first item of an album carries the caption, the other items are left as they are.
+		chatId, messageId := record.ChatId, record.TelegramMessageId
+		if len(record.TelegramMessageIds) > 0 {
+			messageId = record.TelegramMessageIds[0]
+		}
+		// The delivered copy is edited as what it was sent as, the media of the message may have changed since.
+		msgType := models.MessageType(record.MessageType)
+
+		chat := models.Chat{Id: chatId}
+		if found, _ := chatService.FindById(chatId); found != nil {
+			chat = *found
+		}
+		text, opts, renderErr := c.renderMessage(msg, record.LanguageId, c.templateData(chat, record.GroupId))
+
+		return delivery.Task{
+			ChatId: chatId,
+			Send: func() ([]tele.Message, error) {
+				if renderErr != nil {
+					return nil, renderErr
+				}
+				if text == "" {
+					return nil, errors.New(models.SkipReasonMissingLanguage)
+				}
+
+				var result *tele.Message
+				var err error
+				if msgType == models.TextMessage {
+					result, err = c.EditText(chatId, messageId, text, opts)
+				} else {
+					result, err = c.EditCaption(chatId, messageId, text, opts)
+				}
+
+				if errors.Is(err, tele.ErrMessageNotModified) {
+					return nil, nil
+				}
+				if err != nil {
+					return nil, err
+				}
+
+				return []tele.Message{*result}, nil
+			},
+		}
+	}
+
+	c.runOnDeliveries(initiatorId, broadcast, "Edit", "edited", records, task, nil)
+
+	logger.Debug("Edit queued", zap.Int("tasks", len(records)))
+
+	return fmt.Sprintf("Edit of broadcast [%d] started: %d chats.", broadcast.Id, len(records)), nil
+}
+
+// RecallBroadcast deletes every delivered copy of the broadcast, a running broadcast is stopped first.
+func (c *Controller) RecallBroadcast(initiatorId int64, broadcastId uint64) (string, error) {
+	logger := c.Logger.With(
+		zap.String("function", "RecallBroadcast"),
+		zap.Int64("initiatorID", initiatorId),
+		zap.Uint64("broadcastID", broadcastId),
+	)
+
+	logger.Debug("Recalling broadcast")
+
+	// The chats the broadcast has not reached yet are cancelled,
+	// so every copy delivered so far is recorded before it is recalled.
+	c.Delivery.Cancel(broadcastId)
+
+	broadcast, records, err := c.findDelivered(broadcastId)
+	if err != nil {
+		logger.Error("Failed to find delivered messages", zap.Error(err))
+		return "", err
+	}
+
+	var deliveryService = c.CreateDeliveryService()
+
+	task := func(record models.Delivery) delivery.Task {
+		chatId, messageIds := record.ChatId, record.TelegramMessageIds
+		if len(messageIds) == 0 {
+			messageIds = []int{record.TelegramMessageId}
+		}
+
+		return delivery.Task{
+			ChatId: chatId,
+			Send: func() ([]tele.Message, error) {
+				for i, messageId := range messageIds {
+					// A message deleted by hand in the chat is already recalled.
+					err := c.DeleteMessage(chatId, messageId)
+					if err == nil || errors.Is(err, tele.ErrNotFoundToDelete) {
+						continue
+					}
+
+					// The deleted messages of an album are dropped from the delivery,
+					// so another recall only deletes the rest.
+					if i > 0 {
+						record.TelegramMessageIds = messageIds[i:]
+						record.TelegramMessageId = messageIds[i]
+						if _, updateErr := deliveryService.Update(&record); updateErr != nil {
+							logger.Error("Failed to update delivery", zap.Error(updateErr))
+						}
+					}
+
+					return nil, fmt.Errorf("%d of %d messages deleted: %w", i, len(messageIds), err)
+				}
+				return nil, nil
+			},
+		}
+	}
+
+	onSuccess := func(record models.Delivery) {
+		record.Status = models.DeliveryStatusRecalled
+		if _, err := deliveryService.Update(&record); err != nil {
+			logger.Error("Failed to update delivery", zap.Error(err))
+		}
+	}
+
+	c.runOnDeliveries(initiatorId, broadcast, "Recall", "deleted", records, task, onSuccess)
+
+	logger.Debug("Recall queued", zap.Int("tasks", len(records)))
+
+	return fmt.Sprintf("Recall of broadcast [%d] started: %d chats.", broadcast.Id, len(records)), nil
+}
+
+// findDelivered returns the broadcast and its successfully sent deliveries.
+func (c *Controller) findDelivered(broadcastId uint64) (*models.Broadcast, []models.Delivery, error) {
+	broadcast, _ := c.CreateBroadcastService().FindById(broadcastId)
+	if broadcast == nil {
+		return nil, nil, constants.ErrNotFound
+	}
+
+	deliveries, _ := c.CreateDeliveryService().FindBy("broadcast_id = ? AND status = ?", fmt.Sprint(broadcast.Id), models.DeliveryStatusSent)
+
+	var records []models.Delivery
+	for _, record := range deliveries {
+		if record.TelegramMessageId != 0 {
+			records = append(records, record)
+		}
+	}
+
+	if len(records) == 0 {
+		return nil, nil, errors.New("no delivered messages")
+	}
+
+	return broadcast, records, nil
+}
+
+// runOnDeliveries queues a task for every delivery record and reports
+// per-chat results to the initiator once all of them are processed.
+func (c *Controller) runOnDeliveries(initiatorId int64, broadcast *models.Broadcast, action string, done string, records []models.Delivery, task func(models.Delivery) delivery.Task, onSuccess func(models.Delivery)) {
+	logger := c.Logger.With(
+		zap.String("function", "runOnDeliveries"),
+		zap.Uint64("broadcastID", broadcast.Id),
+		zap.String("action", action),
+	)
+
+	var chatService = c.CreateChatService()
+
+	var tasks []delivery.Task
+	byChat := make(map[int64]models.Delivery)
+
+	for _, record := range records {
+		byChat[record.ChatId] = record
+		tasks = append(tasks, task(record))
+	}
+
+	c.Delivery.Submit(&delivery.Job{
+		Id:    broadcast.Id,
+		Tasks: tasks,
+		OnProgress: func(processed int, total int) {
+			c.Notify(initiatorId, fmt.Sprintf("%s of broadcast [%d]: %d/%d processed.", action, broadcast.Id, processed, total))
+		},
+		OnDone: func(results []delivery.Result) {
+			counter := 0
+			failed := []string{}
+
+			for _, result := range results {
+				record := byChat[result.Task.ChatId]
+
+				if result.Err != nil {
+					logger.Error("Failed to process delivery", zap.Any("delivery", record), zap.Error(result.Err))
+
+					name := fmt.Sprint(record.ChatId)
+					if chat, _ := chatService.FindById(record.ChatId); chat != nil {
+						name = chat.Name
+					}
+					failed = append(failed, fmt.Sprintf("%s (%s)", name, result.Err.Error()))
+					continue
+				}
+
+				counter++
+				if onSuccess != nil {
+					onSuccess(record)
+				}
+			}
+
+			response := fmt.Sprintf("%s of broadcast [%d] finished.\n%d messages %s.", action, broadcast.Id, counter, done)
+			if len(failed) > 0 {
+				response += fmt.Sprintf("\nFailed: %s", strings.Join(failed, ", "))
+			}
+
+			logger.Debug("Finished", zap.String("response", response))
+
+			// Failing to edit or delete an old message does not tell
+			// whether the chat still takes new ones, the chats stay as they are.
+			c.Notify(initiatorId, response)
+		},
+	})
+}
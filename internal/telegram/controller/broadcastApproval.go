@@ -0,0 +1,299 @@
+package controller
+
+import (
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/models"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	tele "gopkg.in/telebot.v3"
+)
+
+// approvalMutex makes approving, rejecting and expiring an approval happen once.
+var approvalMutex sync.Mutex
+
+// needsApproval reports whether broadcasts of the initiator wait for an approval.
+func (c *Controller) needsApproval(initiatorId int64) bool {
+	user, _ := c.CreateUserService().FindById(initiatorId)
+	return user != nil && !user.IsMaster
+}
+
+// CheckApprovers makes sure every configured approver is an admin,
+// the buttons of the approval only reach the admins.
+func (c *Controller) CheckApprovers() error {
+	userService := c.CreateUserService()
+
+	for _, approverId := range c.Approvers {
+		if user, _ := userService.FindById(approverId); user == nil {
+			return fmt.Errorf("approver [%d] is not an admin", approverId)
+		}
+	}
+
+	return nil
+}
+
+// isApprover reports whether the user approves broadcasts: the configured approvers,
+// or the masters if there are none. Approvers removed from the admins do not count.
+func (c *Controller) isApprover(userId int64) bool {
+	user, _ := c.CreateUserService().FindById(userId)
+	if user == nil {
+		return false
+	}
+
+	if len(c.Approvers) > 0 {
+		for _, approverId := range c.Approvers {
+			if approverId == userId {
+				return true
+			}
+		}
+		return false
+	}
+
+	return user.IsMaster
+}
+
+// approvers returns the approvers but the initiator, nobody approves their own broadcast.
+func (c *Controller) approvers(initiatorId int64) []int64 {
+	var result []int64
+
+	userService := c.CreateUserService()
+
+	if len(c.Approvers) > 0 {
+		for _, approverId := range c.Approvers {
+			if approverId == initiatorId {
+				continue
+			}
+			if user, _ := userService.FindById(approverId); user != nil {
+				result = append(result, approverId)
+			}
+		}
+		return result
+	}
+
+	users, _ := userService.FindAll()
+	for _, user := range users {
+		if user.IsMaster && user.Id != initiatorId {
+			result = append(result, user.Id)
+		}
+	}
+
+	return result
+}
+
+// requestApproval stores the confirmed broadcast as pending and sends the approvers
+// a sample and the dry run of it with the Approve and Reject buttons.
+func (c *Controller) requestApproval(request *models.BroadcastRequest) (string, error) {
+	logger := c.Logger.With(
+		zap.String("function", "requestApproval"),
+		zap.Int64("initiatorID", request.InitiatorId),
+		zap.Uint64("messageID", request.MessageId),
+		zap.String("target", request.Target.String()),
+	)
+
+	logger.Debug("Requesting approval")
+
+	approvers := c.approvers(request.InitiatorId)
+	if len(approvers) == 0 {
+		return "", errors.New("no approvers configured")
+	}
+
+	msg, _ := c.CreateMessageService().FindById(request.MessageId)
+	if msg == nil {
+		logger.Warn("message not found")
+		return "", constants.ErrNotFound
+	}
+
+	plan, err := c.planBroadcast(msg, &request.Target)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	approval, err := c.CreateApprovalService().Add(&models.Approval{
+		MessageId:   msg.Id,
+		Target:      request.Target.String(),
+		InitiatorId: request.InitiatorId,
+		Status:      models.ApprovalStatusPending,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(c.approvalTTL()),
+	})
+	if err != nil {
+		logger.Error("Failed to add approval", zap.Error(err))
+		return "", err
+	}
+
+	markup := &tele.ReplyMarkup{InlineKeyboard: [][]tele.InlineButton{{
+		{Unique: constants.CallbackBroadcastApprove, Text: "Approve", Data: strconv.FormatUint(approval.Id, 10)},
+		{Unique: constants.CallbackBroadcastReject, Text: "Reject", Data: strconv.FormatUint(approval.Id, 10)},
+	}}}
+
+	sampleLanguageId, hasSample := plan.sampleLanguage()
+	preview := fmt.Sprintf("Approval [%d] requested by %s, it expires at %s.\n%s\nApprove to start the broadcast.",
+		approval.Id, c.userName(request.InitiatorId), approval.ExpiresAt.Format(constants.ScheduleTimeLayout),
+		c.broadcastPreview(msg, &request.Target, plan, sampleLanguageId, hasSample))
+
+	notified := 0
+	for _, approverId := range approvers {
+		if hasSample {
+			if err := c.sendSample(approverId, msg, &request.Target, plan, sampleLanguageId); err != nil {
+				logger.Error("Failed to send sample to approver", zap.Int64("approver", approverId), zap.Error(err))
+			}
+		}
+
+		if _, err := c.SendText(approverId, preview, &tele.SendOptions{ReplyMarkup: markup}); err != nil {
+			logger.Error("Failed to notify approver", zap.Int64("approver", approverId), zap.Error(err))
+			continue
+		}
+		notified++
+	}
+
+	logger.Debug("Approval requested", zap.Uint64("approvalID", approval.Id), zap.Int("approvers", notified))
+
+	return fmt.Sprintf("Approval [%d] requested from %d approvers, it expires at %s.",
+		approval.Id, notified, approval.ExpiresAt.Format(constants.ScheduleTimeLayout)), nil
+}
+
+// ApproveBroadcast starts the broadcast of the approval on behalf of its initiator,
+// the result tells the initiator and the approver whether it has started.
+// The initiator may have lost the send rights meanwhile, they are checked again.
+func (c *Controller) ApproveBroadcast(reviewerId int64, approvalId uint64) (string, error) {
+	approval, err := c.reviewApproval(reviewerId, approvalId, models.ApprovalStatusApproved)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := models.ParseTarget(strings.Split(approval.Target, ";"))
+	if err == nil {
+		err = c.checkInitiator(approval.InitiatorId, target)
+	}
+	if err == nil {
+		approval.Result, err = c.BroadcastMessage(approval.InitiatorId, approval.MessageId, target)
+	}
+	if err != nil {
+		approval.Result = fmt.Sprintf("Error: %s", err.Error())
+	}
+
+	if _, err := c.CreateApprovalService().Update(approval); err != nil {
+		c.Logger.Error("Failed to update approval", zap.Any("approval", approval), zap.Error(err))
+	}
+
+	c.Notify(approval.InitiatorId, fmt.Sprintf("Approval [%d] approved by %s.\n%s", approval.Id, c.userName(reviewerId), approval.Result))
+
+	return approval.Result, nil
+}
+
+// RejectBroadcast drops the broadcast of the approval.
+func (c *Controller) RejectBroadcast(reviewerId int64, approvalId uint64) error {
+	approval, err := c.reviewApproval(reviewerId, approvalId, models.ApprovalStatusRejected)
+	if err != nil {
+		return err
+	}
+
+	c.Notify(approval.InitiatorId, fmt.Sprintf("Approval [%d] rejected by %s.", approval.Id, c.userName(reviewerId)))
+
+	return nil
+}
+
+// ExpireApprovals marks the approvals nobody has answered in time as expired.
+func (c *Controller) ExpireApprovals(now time.Time) {
+	logger := c.Logger.With(
+		zap.String("function", "ExpireApprovals"),
+	)
+
+	approvalMutex.Lock()
+	defer approvalMutex.Unlock()
+
+	approvalService := c.CreateApprovalService()
+	approvals, _ := approvalService.FindAll()
+
+	for _, approval := range approvals {
+		if approval.Status != models.ApprovalStatusPending || approval.ExpiresAt.After(now) {
+			continue
+		}
+
+		approval.Status = models.ApprovalStatusExpired
+		approval.Result = "not approved in time"
+		if _, err := approvalService.Update(&approval); err != nil {
+			logger.Error("Failed to expire approval", zap.Any("approval", approval), zap.Error(err))
+			continue
+		}
+
+		logger.Debug("Approval expired", zap.Any("approval", approval))
+
+		c.Notify(approval.InitiatorId, fmt.Sprintf("Approval [%d] expired: message [%d] to %s was not approved in time.",
+			approval.Id, approval.MessageId, approval.Target))
+	}
+}
+
+// checkInitiator makes sure the initiator is still an admin allowed to send to the target.
+func (c *Controller) checkInitiator(initiatorId int64, target *models.Target) error {
+	user, _ := c.CreateUserService().FindById(initiatorId)
+	if user == nil {
+		return errors.New("initiator is no longer an admin")
+	}
+
+	if !user.CanTarget(models.PermissionSend, target) {
+		return fmt.Errorf("initiator has %s to send to %s", constants.ErrNoPermission, target)
+	}
+
+	return nil
+}
+
+// reviewApproval moves the pending approval to the status, so only the first answer counts.
+func (c *Controller) reviewApproval(reviewerId int64, approvalId uint64, status string) (*models.Approval, error) {
+	if !c.isApprover(reviewerId) {
+		return nil, constants.ErrNoPermission
+	}
+
+	approvalMutex.Lock()
+	defer approvalMutex.Unlock()
+
+	approvalService := c.CreateApprovalService()
+
+	approval, _ := approvalService.FindById(approvalId)
+	if approval == nil {
+		return nil, errors.New("approval not found")
+	}
+
+	if approval.InitiatorId == reviewerId {
+		return nil, errors.New("cannot approve your own broadcast")
+	}
+
+	if approval.Status != models.ApprovalStatusPending {
+		return nil, fmt.Errorf("approval is already %s", approval.Status)
+	}
+
+	if time.Now().After(approval.ExpiresAt) {
+		return nil, errors.New("approval expired")
+	}
+
+	approval.Status = status
+	approval.ReviewerId = reviewerId
+	if _, err := approvalService.Update(approval); err != nil {
+		return nil, err
+	}
+
+	return approval, nil
+}
+
+func (c *Controller) approvalTTL() time.Duration {
+	if c.ApprovalTTL > 0 {
+		return c.ApprovalTTL
+	}
+
+	return constants.ApprovalTTL
+}
+
+func (c *Controller) userName(userId int64) string {
+	name := "?"
+	if user, _ := c.CreateUserService().FindById(userId); user != nil {
+		name = user.Name
+	}
+
+	return fmt.Sprintf("[%d] %s", userId, name)
+}
@@ -0,0 +1,282 @@
+package controller
+
+import (
+	"DC_NewsSender/internal/telegram/cache"
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/models"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"go.uber.org/zap"
+	tele "gopkg.in/telebot.v3"
+)
+
+// RequestBroadcast sends the initiator a sample of the message and the dry run
+// of the broadcast, the message is broadcast once the initiator confirms it.
+func (c *Controller) RequestBroadcast(initiatorId int64, msgId uint64, target *models.Target) error {
+	logger := c.Logger.With(
+		zap.String("function", "RequestBroadcast"),
+		zap.Int64("initiatorID", initiatorId),
+		zap.Uint64("messageID", msgId),
+		zap.String("target", target.String()),
+	)
+
+	logger.Debug("Requesting broadcast")
+
+	msg, _ := c.CreateMessageService().FindById(msgId)
+	if msg == nil {
+		logger.Warn("message not found")
+		return constants.ErrNotFound
+	}
+
+	plan, err := c.planBroadcast(msg, target)
+	if err != nil {
+		return err
+	}
+
+	if len(plan.chats)+len(plan.skipped) == 0 {
+		return errors.New("no chats match the target")
+	}
+
+	// The sample shows how the message looks for the first chat of the language
+	// and fails early if Telegram rejects it.
+	sampleLanguageId, hasSample := plan.sampleLanguage()
+	if hasSample {
+		if err := c.sendSample(initiatorId, msg, target, plan, sampleLanguageId); err != nil {
+			logger.Error("Failed to send sample", zap.Error(err))
+			return fmt.Errorf("failed to send the sample: %w", err)
+		}
+	}
+
+	dropExpiredBroadcastRequests()
+
+	now := time.Now()
+	request := models.BroadcastRequest{
+		Id:          strconv.FormatInt(now.UnixNano(), 36),
+		InitiatorId: initiatorId,
+		MessageId:   msg.Id,
+		Target:      *target,
+		CreatedAt:   now,
+	}
+	cache.BroadcastRequests.Add(request.Id, request)
+
+	markup := &tele.ReplyMarkup{InlineKeyboard: [][]tele.InlineButton{{
+		{Unique: constants.CallbackBroadcastConfirm, Text: "Confirm", Data: request.Id},
+		{Unique: constants.CallbackBroadcastCancel, Text: "Cancel", Data: request.Id},
+	}}}
+
+	preview := c.broadcastPreview(msg, target, plan, sampleLanguageId, hasSample)
+	if c.needsApproval(initiatorId) {
+		preview += "\nConfirm to ask for an approval of the broadcast."
+	} else {
+		preview += "\nConfirm to start the broadcast."
+	}
+	if _, err := c.SendText(initiatorId, preview, &tele.SendOptions{ReplyMarkup: markup}); err != nil {
+		cache.BroadcastRequests.Remove(request.Id)
+		return err
+	}
+
+	logger.Debug("Broadcast requested", zap.String("requestID", request.Id), zap.Int("chats", len(plan.chats)))
+
+	return nil
+}
+
+// ConfirmBroadcast starts the requested broadcast,
+// broadcasts of admins who are not masters wait for an approval first.
+func (c *Controller) ConfirmBroadcast(initiatorId int64, requestId string) (string, error) {
+	request, err := takeBroadcastRequest(initiatorId, requestId)
+	if err != nil {
+		return "", err
+	}
+
+	// The initiator may have lost the send rights since the request, StartBroadcast decides on the approval again.
+	if err := c.checkInitiator(request.InitiatorId, &request.Target); err != nil {
+		return "", err
+	}
+
+	return c.StartBroadcast(request.InitiatorId, request.MessageId, &request.Target)
+}
+
+// StartBroadcast starts the broadcast of the message to the target,
+// or asks for an approval first if the initiator is not a master.
+func (c *Controller) StartBroadcast(initiatorId int64, messageId uint64, target *models.Target) (string, error) {
+	if c.needsApproval(initiatorId) {
+		return c.requestApproval(&models.BroadcastRequest{
+			InitiatorId: initiatorId,
+			MessageId:   messageId,
+			Target:      *target,
+			CreatedAt:   time.Now(),
+		})
+	}
+
+	return c.BroadcastMessage(initiatorId, messageId, target)
+}
+
+// CancelBroadcast drops the requested broadcast.
+func (c *Controller) CancelBroadcast(initiatorId int64, requestId string) error {
+	_, err := takeBroadcastRequest(initiatorId, requestId)
+	return err
+}
+
+// takeBroadcastRequest removes the request of the initiator,
+// so it is confirmed or cancelled only once.
+func takeBroadcastRequest(initiatorId int64, requestId string) (*models.BroadcastRequest, error) {
+	request := cache.BroadcastRequests.Find(requestId)
+	if request == nil {
+		return nil, errors.New("broadcast request not found")
+	}
+
+	if request.InitiatorId != initiatorId {
+		return nil, errors.New("only the initiator can answer the broadcast request")
+	}
+
+	request = cache.BroadcastRequests.Take(requestId)
+	if request == nil {
+		return nil, errors.New("broadcast request not found")
+	}
+
+	if time.Since(request.CreatedAt) > constants.BroadcastRequestTTL {
+		return nil, errors.New("broadcast request expired")
+	}
+
+	return request, nil
+}
+
+func (c *Controller) sendSample(chatId int64, msg *models.Message, target *models.Target, plan *broadcastPlan, languageId uint64) error {
+	for _, chat := range plan.chats {
+		if plan.languages[chat.Id] != languageId {
+			continue
+		}
+
+		text, opts, err := c.renderMessage(msg, languageId, c.templateData(chat, target.GroupOf(chat)))
+		if err != nil {
+			return err
+		}
+
+		_, err = c.SendMessage(chatId, msg.MediaFor(languageId), text, opts)
+		return err
+	}
+
+	return nil
+}
+
+func dropExpiredBroadcastRequests() {
+	for _, request := range cache.BroadcastRequests.FindAll() {
+		if time.Since(request.CreatedAt) > constants.BroadcastRequestTTL {
+			cache.BroadcastRequests.Remove(request.Id)
+		}
+	}
+}
+
+// broadcastPreview describes the dry run of the broadcast: the chats of every
+// language the message is delivered to and the skipped chats with the reasons.
+func (c *Controller) broadcastPreview(msg *models.Message, target *models.Target, plan *broadcastPlan, sampleLanguageId uint64, hasSample bool) string {
+	var chatService = c.CreateChatService()
+
+	var preview strings.Builder
+	preview.WriteString(fmt.Sprintf("Dry run of message [%d] to %s.", msg.Id, target))
+
+	counts := make(map[uint64]int)
+	var languageIds []uint64
+	for _, chat := range plan.chats {
+		languageId := plan.languages[chat.Id]
+		if counts[languageId] == 0 {
+			languageIds = append(languageIds, languageId)
+		}
+		counts[languageId]++
+	}
+	sort.Slice(languageIds, func(i, j int) bool { return languageIds[i] < languageIds[j] })
+
+	preview.WriteString(fmt.Sprintf("\nDeliver to %d chats:", len(plan.chats)))
+	for _, languageId := range languageIds {
+		preview.WriteString(fmt.Sprintf("\n %s: %d", c.languageName(languageId), counts[languageId]))
+	}
+	for i, chat := range plan.chats {
+		if i == constants.PreviewChatLimit {
+			preview.WriteString(fmt.Sprintf("\n ... and %d more", len(plan.chats)-i))
+			break
+		}
+		preview.WriteString(fmt.Sprintf("\n [%d] %s", chat.Id, chat.Name))
+	}
+
+	fallbacks := plan.fallbacks()
+	if len(fallbacks) > 0 {
+		preview.WriteString(fmt.Sprintf("\nFallback texts for %d chats:", len(fallbacks)))
+	}
+	for i, chat := range fallbacks {
+		if i == constants.PreviewChatLimit {
+			preview.WriteString(fmt.Sprintf("\n ... and %d more", len(fallbacks)-i))
+			break
+		}
+		preview.WriteString(fmt.Sprintf("\n %s", c.describeFallback(chat, plan.languages[chat.Id])))
+	}
+
+	if len(plan.skipped) > 0 {
+		preview.WriteString(fmt.Sprintf("\nSkip %d chats:", len(plan.skipped)))
+	}
+	for i, record := range plan.skipped {
+		if i == constants.PreviewChatLimit {
+			preview.WriteString(fmt.Sprintf("\n ... and %d more", len(plan.skipped)-i))
+			break
+		}
+
+		var name string
+		if chat, _ := chatService.FindById(record.ChatId); chat != nil {
+			name = chat.Name
+		}
+
+		reason := record.Error
+		if reason == models.SkipReasonMissingLanguage {
+			reason = fmt.Sprintf("%s %s", reason, c.languageName(record.LanguageId))
+		}
+
+		preview.WriteString(fmt.Sprintf("\n [%d] %s: %s", record.ChatId, name, reason))
+	}
+
+	if hasSample {
+		preview.WriteString(fmt.Sprintf("\nThe sample above is the %s text.", c.languageName(sampleLanguageId)))
+	}
+
+	return capPreview(preview.String(), constants.PreviewTextLimit)
+}
+
+// capPreview keeps the whole lines of the preview which fit into the limit of characters,
+// Telegram counts them in UTF-16 code units.
+func capPreview(preview string, limit int) string {
+	lines := strings.Split(preview, "\n")
+
+	// The last line tells how many lines are left out, so it is kept room for.
+	limit -= len(fmt.Sprintf("\n ... and %d more", len(lines)))
+
+	length := 0
+	for i, line := range lines {
+		lineLength := 0
+		for _, r := range line {
+			lineLength += utf16.RuneLen(r)
+		}
+		if i > 0 {
+			lineLength++
+		}
+
+		if length+lineLength > limit {
+			return fmt.Sprintf("%s\n ... and %d more", strings.Join(lines[:i], "\n"), len(lines)-i)
+		}
+		length += lineLength
+	}
+
+	return preview
+}
+
+func (c *Controller) languageName(languageId uint64) string {
+	name := "?"
+	if lang, _ := c.CreateLanguageService().FindById(languageId); lang != nil {
+		name = lang.Name
+	}
+
+	return fmt.Sprintf("[%d] %s", languageId, name)
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCapPreview(t *testing.T) {
+	tests := []struct {
+		name    string
+		preview string
+		limit   int
+		want    string
+	}{
+		{
+			name:    "fits",
+			preview: "Dry run\n [1] a\n [2] b",
+			limit:   100,
+			want:    "Dry run\n [1] a\n [2] b",
+		},
+		{
+			name:    "cut at a whole line",
+			preview: "Dry run\n" + strings.Repeat("x", 20) + "\n" + strings.Repeat("y", 20),
+			limit:   50,
+			want:    "Dry run\n" + strings.Repeat("x", 20) + "\n ... and 1 more",
+		},
+		{
+			name:    "surrogate pairs count twice",
+			preview: "Dry run\n" + strings.Repeat("😀", 10),
+			limit:   40,
+			want:    "Dry run\n ... and 1 more",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := capPreview(test.preview, test.limit); got != test.want {
+				t.Errorf("capPreview() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
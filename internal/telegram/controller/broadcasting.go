@@ -0,0 +1,614 @@
+package controller
+
+import (
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/delivery"
+	"DC_NewsSender/internal/telegram/models"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	tele "gopkg.in/telebot.v3"
+)
+
+// BroadcastMessage queues the stashed message for every active chat of the target
+// and returns right away. Progress and the final report are sent to the initiator.
+func (c *Controller) BroadcastMessage(initiatorId int64, msgId uint64, target *models.Target) (string, error) {
+	logger := c.Logger.With(
+		zap.String("function", "BroadcastMessage"),
+		zap.Int64("initiatorID", initiatorId),
+		zap.Uint64("messageID", msgId),
+		zap.String("target", target.String()),
+	)
+
+	logger.Debug("Sending message")
+
+	msg, _ := c.CreateMessageService().FindById(msgId)
+	if msg == nil {
+		logger.Warn("message not found")
+		return "", constants.ErrNotFound
+	}
+	logger.Debug("Message found", zap.Any("msg", msg))
+
+	plan, err := c.planBroadcast(msg, target)
+	if err != nil {
+		return "", err
+	}
+	logger.Debug("Broadcast planned", zap.Any("chats", plan.chats), zap.Any("skipped", plan.skipped))
+
+	var tasks []delivery.Task
+	for _, chat := range plan.chats {
+		tasks = append(tasks, c.deliveryTask(chat, target.GroupOf(chat), msg, plan.languages[chat.Id]))
+	}
+
+	broadcast, err := c.CreateBroadcastService().Add(&models.Broadcast{
+		MessageId:   msg.Id,
+		Target:      target.String(),
+		InitiatorId: initiatorId,
+		Status:      models.BroadcastStatusRunning,
+		Total:       len(tasks),
+	})
+	if err != nil {
+		logger.Error("Failed to add broadcast", zap.Error(err))
+		return "", err
+	}
+
+	var deliveryService = c.CreateDeliveryService()
+	for _, record := range plan.skipped {
+		record.BroadcastId = broadcast.Id
+		if _, err := deliveryService.Add(&record); err != nil {
+			logger.Error("Failed to add delivery", zap.Error(err))
+		}
+	}
+
+	c.Delivery.Submit(&delivery.Job{
+		Id:    broadcast.Id,
+		Tasks: tasks,
+		OnResult: func(result delivery.Result) {
+			c.recordDelivery(broadcast, msg, target, plan, result)
+		},
+		OnProgress: func(done int, total int) {
+			c.Notify(initiatorId, fmt.Sprintf("Broadcast [%d]: %d/%d processed.", broadcast.Id, done, total))
+		},
+		OnDone: func(results []delivery.Result) {
+			c.finishBroadcast(broadcast, target, plan, results)
+		},
+	})
+
+	logger.Debug("Broadcast queued", zap.Uint64("broadcastID", broadcast.Id))
+
+	return fmt.Sprintf("Broadcast [%d] started: %d chats of %s.", broadcast.Id, len(tasks), target), nil
+}
+
+// ResolveTarget returns the chats the target selects sorted by id.
+// A chat in several of the target groups is returned once, so it gets a broadcast once.
+func (c *Controller) ResolveTarget(target *models.Target) ([]models.Chat, error) {
+	chats, err := c.CreateChatService().FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(chats, func(i, j int) bool { return chats[i].Id < chats[j].Id })
+
+	var result []models.Chat
+	for _, chat := range chats {
+		if target.Selects(chat) {
+			result = append(result, chat)
+		}
+	}
+
+	return result, nil
+}
+
+// broadcastPlan splits the chats of a target into the chats the message
+// is delivered to and the skipped ones.
+type broadcastPlan struct {
+	chats []models.Chat
+	// languages maps a chat to the language of the text it gets,
+	// it differs from the language of the chat if a fallback is used.
+	languages map[int64]uint64
+	// byId finds the chat of a delivery task.
+	byId    map[int64]models.Chat
+	skipped []models.Delivery
+}
+
+func (c *Controller) planBroadcast(msg *models.Message, target *models.Target) (*broadcastPlan, error) {
+	chats, err := c.ResolveTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &broadcastPlan{languages: make(map[int64]uint64), byId: make(map[int64]models.Chat)}
+
+	for _, chat := range chats {
+		record := models.Delivery{ChatId: chat.Id, GroupId: target.GroupOf(chat), LanguageId: chat.LanguageId, Status: models.DeliveryStatusSkipped}
+
+		if !chat.IsActive {
+			record.Error = models.SkipReasonInactive
+			plan.skipped = append(plan.skipped, record)
+			continue
+		}
+
+		languageId, ok := c.textLanguage(msg, chat.LanguageId, target.Strict)
+		if !ok {
+			record.Error = models.SkipReasonMissingLanguage
+			plan.skipped = append(plan.skipped, record)
+			continue
+		}
+
+		plan.chats = append(plan.chats, chat)
+		plan.languages[chat.Id] = languageId
+		plan.byId[chat.Id] = chat
+	}
+
+	return plan, nil
+}
+
+// chat returns the planned chat of the delivery task.
+func (p *broadcastPlan) chat(chatId int64) models.Chat {
+	if chat, ok := p.byId[chatId]; ok {
+		return chat
+	}
+
+	return models.Chat{Id: chatId}
+}
+
+// fallbacks returns the chats which get a text of a fallback language.
+func (p *broadcastPlan) fallbacks() []models.Chat {
+	var result []models.Chat
+	for _, chat := range p.chats {
+		if p.languages[chat.Id] != chat.LanguageId {
+			result = append(result, chat)
+		}
+	}
+
+	return result
+}
+
+// describeFallback names the chat with its language and the language of the text it got.
+func (c *Controller) describeFallback(chat models.Chat, languageId uint64) string {
+	return fmt.Sprintf("%s [%d] (%s -> %s)", chat.Name, chat.Id, c.languageName(chat.LanguageId), c.languageName(languageId))
+}
+
+// textLanguage returns the language of the text a chat of the language gets: the language
+// itself or the first language of its fallback chain the message has a text in.
+// Strict broadcasts do not use fallbacks.
+func (c *Controller) textLanguage(msg *models.Message, languageId uint64, strict bool) (uint64, bool) {
+	var langService = c.CreateLanguageService()
+
+	// A chain is not supposed to loop, visited guards against one nevertheless.
+	visited := make(map[uint64]bool)
+
+	for id := languageId; !visited[id]; {
+		if msg.Text[id] != "" {
+			return id, true
+		}
+
+		if strict {
+			break
+		}
+		visited[id] = true
+
+		lang, _ := langService.FindById(id)
+		if lang == nil || lang.FallbackId == nil {
+			break
+		}
+		id = *lang.FallbackId
+	}
+
+	return 0, false
+}
+
+// sampleLanguage returns the language most chats of the plan get the message in.
+func (p *broadcastPlan) sampleLanguage() (uint64, bool) {
+	counts := make(map[uint64]int)
+	for _, chat := range p.chats {
+		counts[p.languages[chat.Id]]++
+	}
+
+	var languageId uint64
+	found := false
+	for id, count := range counts {
+		if !found || count > counts[languageId] || count == counts[languageId] && id < languageId {
+			languageId, found = id, true
+		}
+	}
+
+	return languageId, found
+}
+
+// recordDelivery stores the outcome of the delivery to a chat as soon as it is known.
+func (c *Controller) recordDelivery(broadcast *models.Broadcast, msg *models.Message, target *models.Target, plan *broadcastPlan, result delivery.Result) {
+	logger := c.Logger.With(
+		zap.String("function", "recordDelivery"),
+		zap.Uint64("broadcastID", broadcast.Id),
+		zap.Int64("chat", result.Task.ChatId),
+	)
+
+	chat := plan.chat(result.Task.ChatId)
+
+	record := models.Delivery{
+		BroadcastId: broadcast.Id,
+		ChatId:      chat.Id,
+		GroupId:     target.GroupOf(chat),
+		LanguageId:  plan.languages[chat.Id],
+		Status:      models.DeliveryStatusSent,
+	}
+
+	switch {
+	case errors.Is(result.Err, delivery.ErrCancelled):
+		record.Status = models.DeliveryStatusSkipped
+		record.Error = models.SkipReasonCancelled
+	case result.Err != nil:
+		logger.Error("Failed to send message", zap.Error(result.Err))
+
+		record.Status = models.DeliveryStatusFailed
+		record.Error = result.Err.Error()
+
+		if _, err := c.CreateFailedDeliveryService().Add(&models.FailedDelivery{
+			BroadcastId: broadcast.Id,
+			ChatId:      chat.Id,
+			Error:       result.Err.Error(),
+		}); err != nil {
+			logger.Error("Failed to add failed delivery", zap.Error(err))
+		}
+	default:
+		record.MessageType = int(msg.GetType(record.LanguageId))
+		record.TelegramMessageIds = messageIds(result.Messages)
+		if len(record.TelegramMessageIds) > 0 {
+			record.TelegramMessageId = record.TelegramMessageIds[0]
+		}
+	}
+
+	if _, err := c.CreateDeliveryService().Add(&record); err != nil {
+		logger.Error("Failed to add delivery", zap.Error(err))
+	}
+}
+
+// finishBroadcast reports the broadcast once every delivery is recorded.
+func (c *Controller) finishBroadcast(broadcast *models.Broadcast, target *models.Target, plan *broadcastPlan, results []delivery.Result) {
+	logger := c.Logger.With(
+		zap.String("function", "finishBroadcast"),
+		zap.Uint64("broadcastID", broadcast.Id),
+	)
+
+	counter, cancelled := 0, 0
+	failed := []string{}
+	fallbacks := []string{}
+
+	for _, result := range results {
+		chat := plan.chat(result.Task.ChatId)
+
+		if errors.Is(result.Err, delivery.ErrCancelled) {
+			cancelled++
+			continue
+		}
+		if result.Err != nil {
+			failed = append(failed, chat.Name)
+			continue
+		}
+
+		counter++
+		if languageId := plan.languages[chat.Id]; languageId != chat.LanguageId {
+			fallbacks = append(fallbacks, c.describeFallback(chat, languageId))
+		}
+	}
+
+	broadcast.Status = models.BroadcastStatusDone
+	if cancelled > 0 {
+		broadcast.Status = models.BroadcastStatusCancelled
+	}
+	broadcast.Sent = counter
+	broadcast.Failed = len(failed)
+	if _, err := c.CreateBroadcastService().Update(broadcast); err != nil {
+		logger.Error("Failed to update broadcast", zap.Error(err))
+	}
+
+	var response string
+	if counter > 0 {
+		response = fmt.Sprintf("%d messages sent to %s.", counter, target)
+	} else {
+		response = fmt.Sprintf("No messages sent to %s.", target)
+	}
+	if len(fallbacks) > 0 {
+		response += fmt.Sprintf("\nFallback texts sent to: %s", strings.Join(fallbacks, ", "))
+	}
+	if len(failed) > 0 {
+		response += fmt.Sprintf("\nFailed to send to: %s", strings.Join(failed, ", "))
+	}
+	if cancelled > 0 {
+		response += fmt.Sprintf("\nCancelled by a recall: %d chats.", cancelled)
+	}
+
+	logger.Debug("Broadcast finished", zap.String("response", response))
+
+	c.Notify(broadcast.InitiatorId, fmt.Sprintf("Broadcast [%d] finished.\n%s", broadcast.Id, response))
+
+	c.handleDeliveryErrors(results)
+}
+
+// InterruptBroadcasts marks the broadcasts left running by a previous run as interrupted,
+// their deliveries recorded so far stay available to the other broadcast commands.
+func (c *Controller) InterruptBroadcasts() {
+	logger := c.Logger.With(
+		zap.String("function", "InterruptBroadcasts"),
+	)
+
+	broadcastService := c.CreateBroadcastService()
+	deliveryService := c.CreateDeliveryService()
+
+	broadcasts, _ := broadcastService.FindAll()
+	for _, broadcast := range broadcasts {
+		if broadcast.Status != models.BroadcastStatusRunning {
+			continue
+		}
+
+		deliveries, _ := deliveryService.FindBy("broadcast_id = ?", fmt.Sprint(broadcast.Id))
+
+		broadcast.Status = models.BroadcastStatusInterrupted
+		broadcast.Sent, broadcast.Failed = 0, 0
+		for _, record := range deliveries {
+			switch record.Status {
+			case models.DeliveryStatusSent:
+				broadcast.Sent++
+			case models.DeliveryStatusFailed:
+				broadcast.Failed++
+			}
+		}
+
+		if _, err := broadcastService.Update(&broadcast); err != nil {
+			logger.Error("Failed to interrupt broadcast", zap.Any("broadcast", broadcast), zap.Error(err))
+			continue
+		}
+
+		logger.Warn("Broadcast interrupted", zap.Any("broadcast", broadcast))
+
+		c.Notify(broadcast.InitiatorId, fmt.Sprintf("Broadcast [%d] was interrupted by a restart: %d of %d messages sent, %d failed.",
+			broadcast.Id, broadcast.Sent, broadcast.Total, broadcast.Failed))
+	}
+}
+
+// retries holds the broadcasts whose failed deliveries are being retried,
+// a second retry of the same broadcast would send the messages twice.
+var (
+	retryMutex sync.Mutex
+	retries    = make(map[uint64]bool)
+)
+
+// startRetry marks the broadcast as being retried, false if it already is.
+func startRetry(broadcastId uint64) bool {
+	retryMutex.Lock()
+	defer retryMutex.Unlock()
+
+	if retries[broadcastId] {
+		return false
+	}
+
+	retries[broadcastId] = true
+	return true
+}
+
+func finishRetrying(broadcastId uint64) {
+	retryMutex.Lock()
+	defer retryMutex.Unlock()
+
+	delete(retries, broadcastId)
+}
+
+// RetryFailed queues the failed deliveries of the broadcast again.
+// Deliveries that succeed are removed from the failed list.
+func (c *Controller) RetryFailed(initiatorId int64, broadcastId uint64) (string, error) {
+	logger := c.Logger.With(
+		zap.String("function", "RetryFailed"),
+		zap.Int64("initiatorID", initiatorId),
+		zap.Uint64("broadcastID", broadcastId),
+	)
+
+	logger.Debug("Retrying failed deliveries")
+
+	broadcast, _ := c.CreateBroadcastService().FindById(broadcastId)
+	if broadcast == nil {
+		logger.Warn("broadcast not found")
+		return "", constants.ErrNotFound
+	}
+
+	if broadcast.Status == models.BroadcastStatusRunning {
+		return "", fmt.Errorf("broadcast [%d] is still running", broadcast.Id)
+	}
+
+	if !startRetry(broadcast.Id) {
+		return "", fmt.Errorf("retry of broadcast [%d] is already running", broadcast.Id)
+	}
+
+	queued := false
+	defer func() {
+		if !queued {
+			finishRetrying(broadcast.Id)
+		}
+	}()
+
+	msg, _ := c.CreateMessageService().FindById(broadcast.MessageId)
+	if msg == nil {
+		logger.Warn("message not found")
+		return "", errors.New("message not found")
+	}
+
+	failedDeliveries, err := c.CreateFailedDeliveryService().FindAll()
+	if err != nil {
+		return "", err
+	}
+
+	var chatService = c.CreateChatService()
+
+	// Fallbacks follow the broadcast, a strict one does not use them on retry either.
+	target, err := models.ParseTarget(strings.Split(broadcast.Target, ";"))
+	if err != nil {
+		target = &models.Target{}
+	}
+
+	var tasks []delivery.Task
+	targets := make(map[int64]models.Chat)
+	records := make(map[int64]models.FailedDelivery)
+	languages := make(map[int64]uint64)
+
+	for _, failedDelivery := range failedDeliveries {
+		if failedDelivery.BroadcastId != broadcast.Id {
+			continue
+		}
+
+		chat, _ := chatService.FindById(failedDelivery.ChatId)
+		if chat == nil || !chat.IsActive {
+			logger.Warn("Skipping failed delivery", zap.Any("delivery", failedDelivery))
+			continue
+		}
+
+		languageId, ok := c.textLanguage(msg, chat.LanguageId, target.Strict)
+		if !ok {
+			logger.Warn("Skipping failed delivery", zap.Any("delivery", failedDelivery))
+			continue
+		}
+
+		targets[chat.Id] = *chat
+		records[chat.Id] = failedDelivery
+		languages[chat.Id] = languageId
+		tasks = append(tasks, c.deliveryTask(*chat, target.GroupOf(*chat), msg, languageId))
+	}
+
+	if len(tasks) == 0 {
+		return "", errors.New("nothing to retry")
+	}
+
+	c.Delivery.Submit(&delivery.Job{
+		Id:    broadcast.Id,
+		Tasks: tasks,
+		OnResult: func(result delivery.Result) {
+			c.recordRetry(broadcast, msg, languages[result.Task.ChatId], records[result.Task.ChatId], result)
+		},
+		OnProgress: func(done int, total int) {
+			c.Notify(initiatorId, fmt.Sprintf("Retry of broadcast [%d]: %d/%d processed.", broadcast.Id, done, total))
+		},
+		OnDone: func(results []delivery.Result) {
+			defer finishRetrying(broadcast.Id)
+			c.finishRetry(initiatorId, broadcast, targets, results)
+		},
+	})
+	queued = true
+
+	logger.Debug("Retry queued", zap.Int("tasks", len(tasks)))
+
+	return fmt.Sprintf("Retry of broadcast [%d] started: %d chats.", broadcast.Id, len(tasks)), nil
+}
+
+// recordRetry stores the outcome of the retried delivery to a chat as soon as it is known.
+func (c *Controller) recordRetry(broadcast *models.Broadcast, msg *models.Message, languageId uint64, record models.FailedDelivery, result delivery.Result) {
+	logger := c.Logger.With(
+		zap.String("function", "recordRetry"),
+		zap.Uint64("broadcastID", broadcast.Id),
+		zap.Int64("chat", record.ChatId),
+	)
+
+	var failedDeliveryService = c.CreateFailedDeliveryService()
+	var deliveryService = c.CreateDeliveryService()
+
+	if errors.Is(result.Err, delivery.ErrCancelled) {
+		return
+	}
+
+	if result.Err != nil {
+		logger.Error("Failed to send message", zap.Error(result.Err))
+
+		record.Error = result.Err.Error()
+		if _, err := failedDeliveryService.Update(&record); err != nil {
+			logger.Error("Failed to update failed delivery", zap.Error(err))
+		}
+		return
+	}
+
+	if err := failedDeliveryService.Remove(record.Id); err != nil {
+		logger.Error("Failed to remove failed delivery", zap.Error(err))
+	}
+
+	deliveries, _ := deliveryService.FindBy("broadcast_id = ? AND chat_id = ?", fmt.Sprint(broadcast.Id), fmt.Sprint(record.ChatId))
+	for _, sent := range deliveries {
+		sent.Status = models.DeliveryStatusSent
+		sent.Error = ""
+		sent.LanguageId = languageId
+		sent.MessageType = int(msg.GetType(languageId))
+		sent.TelegramMessageIds = messageIds(result.Messages)
+		if len(sent.TelegramMessageIds) > 0 {
+			sent.TelegramMessageId = sent.TelegramMessageIds[0]
+		}
+		if _, err := deliveryService.Update(&sent); err != nil {
+			logger.Error("Failed to update delivery", zap.Error(err))
+		}
+	}
+}
+
+// finishRetry reports the retry once every delivery is recorded.
+func (c *Controller) finishRetry(initiatorId int64, broadcast *models.Broadcast, targets map[int64]models.Chat, results []delivery.Result) {
+	logger := c.Logger.With(
+		zap.String("function", "finishRetry"),
+		zap.Uint64("broadcastID", broadcast.Id),
+	)
+
+	counter := 0
+	failed := []string{}
+
+	for _, result := range results {
+		if errors.Is(result.Err, delivery.ErrCancelled) {
+			continue
+		}
+		if result.Err != nil {
+			failed = append(failed, targets[result.Task.ChatId].Name)
+			continue
+		}
+
+		counter++
+	}
+
+	broadcast.Sent += counter
+	broadcast.Failed -= counter
+	if _, err := c.CreateBroadcastService().Update(broadcast); err != nil {
+		logger.Error("Failed to update broadcast", zap.Error(err))
+	}
+
+	response := fmt.Sprintf("Retry of broadcast [%d] finished.\n%d messages sent.", broadcast.Id, counter)
+	if len(failed) > 0 {
+		response += fmt.Sprintf("\nFailed to send to: %s", strings.Join(failed, ", "))
+	}
+
+	logger.Debug("Retry finished", zap.String("response", response))
+
+	c.Notify(initiatorId, response)
+
+	c.handleDeliveryErrors(results)
+}
+
+// deliveryTask renders the language variant of the message for the chat right away,
+// so later edits do not affect deliveries that are already queued.
+func (c *Controller) deliveryTask(chat models.Chat, groupId uint64, msg *models.Message, languageId uint64) delivery.Task {
+	text, opts, err := c.renderMessage(msg, languageId, c.templateData(chat, groupId))
+	media := msg.MediaFor(languageId)
+
+	return delivery.Task{
+		ChatId: chat.Id,
+		Send: func() ([]tele.Message, error) {
+			if err != nil {
+				return nil, err
+			}
+			return c.SendMessage(chat.Id, media, text, opts)
+		},
+	}
+}
+
+func messageIds(msgs []tele.Message) []int {
+	ids := make([]int, 0, len(msgs))
+	for _, msg := range msgs {
+		ids = append(ids, msg.ID)
+	}
+
+	return ids
+}
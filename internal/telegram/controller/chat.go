@@ -154,6 +154,12 @@ func (s *ChatService) Update(chat *models.Chat) (*models.Chat, error) {
 		return nil, err
 	}
 
+	// Save only adds memberships, groups removed from the chat have to be unlinked.
+	if err := s.repo.ReplaceAssociation(&dbChat, "Groups", dbChat.Groups); err != nil {
+		logger.Error("Failed to update chat groups", zap.Error(err))
+		return nil, err
+	}
+
 	logger.Debug("Updated chat", zap.Any("result", result))
 
 	s.cache.Add(chat.Id, *chat)
@@ -176,6 +182,12 @@ func (s *ChatService) Remove(id int64) error {
 		return err
 	}
 
+	dbChat := db_models.Chat(*chatToDelete)
+	if err := s.repo.ReplaceAssociation(&dbChat, "Groups", []db_models.Group{}); err != nil {
+		logger.Error("Failed to remove chat groups", zap.Error(err))
+		return err
+	}
+
 	if err := s.repo.Remove(chatToDelete.Id); err != nil {
 		logger.Error("Failed to remove chat", zap.Error(err))
 		return err
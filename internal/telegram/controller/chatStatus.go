@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/delivery"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// handleDeliveryError deactivates chats the bot cannot reach anymore
+// and follows groups upgraded to supergroups.
+func (c *Controller) handleDeliveryError(chatId int64, err error) {
+	logger := c.Logger.With(
+		zap.String("function", "handleDeliveryError"),
+		zap.Int64("chatID", chatId),
+	)
+
+	if migratedTo := delivery.MigratedTo(err); migratedTo != 0 {
+		if err := c.MigrateChat(chatId, migratedTo); err != nil {
+			logger.Error("Failed to migrate chat", zap.Int64("migratedTo", migratedTo), zap.Error(err))
+		}
+		return
+	}
+
+	if delivery.IsPermanent(err) {
+		if err := c.DeactivateChat(chatId, err.Error()); err != nil {
+			logger.Error("Failed to deactivate chat", zap.Error(err))
+		}
+	}
+}
+
+// DeactivateChat marks the chat inactive with the reason and notifies masters and managers.
+func (c *Controller) DeactivateChat(chatId int64, reason string) error {
+	logger := c.Logger.With(
+		zap.String("function", "DeactivateChat"),
+		zap.Int64("chatID", chatId),
+	)
+
+	logger.Debug("Deactivating chat", zap.String("reason", reason))
+
+	var chatService = c.CreateChatService()
+
+	chat, _ := chatService.FindById(chatId)
+	if chat == nil {
+		return constants.ErrNotFound
+	}
+
+	if !chat.IsActive {
+		logger.Debug("Chat already inactive")
+		return nil
+	}
+
+	now := time.Now()
+	chat.IsActive = false
+	chat.DeactivatedReason = reason
+	chat.DeactivatedAt = &now
+
+	if _, err := chatService.Update(chat); err != nil {
+		return err
+	}
+
+	logger.Debug("Deactivated chat")
+
+	c.NotifyManagers(fmt.Sprintf("Chat %s [%d] has been deactivated: %s\nUse /activatechat to activate it again.", chat.Name, chat.Id, reason), nil)
+
+	return nil
+}
+
+// MigrateChat moves the chat and its delivery records to the id of the supergroup
+// the group chat was upgraded to.
+func (c *Controller) MigrateChat(fromId int64, toId int64) error {
+	logger := c.Logger.With(
+		zap.String("function", "MigrateChat"),
+		zap.Int64("fromID", fromId),
+		zap.Int64("toID", toId),
+	)
+
+	logger.Debug("Migrating chat")
+
+	var chatService = c.CreateChatService()
+
+	chat, _ := chatService.FindById(fromId)
+	if chat == nil {
+		return constants.ErrNotFound
+	}
+
+	if existing, _ := chatService.FindById(toId); existing != nil {
+		return constants.ErrAlreadyExists
+	}
+
+	migrated := *chat
+	migrated.Id = toId
+	if _, err := chatService.Add(&migrated); err != nil {
+		return err
+	}
+
+	if err := chatService.Remove(fromId); err != nil {
+		return err
+	}
+
+	var failedDeliveryService = c.CreateFailedDeliveryService()
+
+	failedDeliveries, _ := failedDeliveryService.FindBy("chat_id = ?", fmt.Sprint(fromId))
+	for i := range failedDeliveries {
+		failedDeliveries[i].ChatId = toId
+		if _, err := failedDeliveryService.Update(&failedDeliveries[i]); err != nil {
+			logger.Error("Failed to update failed delivery", zap.Error(err))
+		}
+	}
+
+	var deliveryService = c.CreateDeliveryService()
+
+	deliveries, _ := deliveryService.FindBy("chat_id = ?", fmt.Sprint(fromId))
+	for i := range deliveries {
+		deliveries[i].ChatId = toId
+		if _, err := deliveryService.Update(&deliveries[i]); err != nil {
+			logger.Error("Failed to update delivery", zap.Error(err))
+		}
+	}
+
+	logger.Debug("Migrated chat")
+
+	c.NotifyManagers(fmt.Sprintf("Chat %s has been upgraded to a supergroup, its id changed from [%d] to [%d].", chat.Name, fromId, toId), nil)
+
+	return nil
+}
+
+// handleDeliveryErrors applies handleDeliveryError to every failed result.
+func (c *Controller) handleDeliveryErrors(results []delivery.Result) {
+	for _, result := range results {
+		if result.Err != nil {
+			c.handleDeliveryError(result.Task.ChatId, result.Err)
+		}
+	}
+}
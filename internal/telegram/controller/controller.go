@@ -3,7 +3,11 @@ package controller
 import (
 	"DC_NewsSender/internal/db/repositories"
 	"DC_NewsSender/internal/telegram/cache"
+	"DC_NewsSender/internal/telegram/delivery"
 	"DC_NewsSender/internal/telegram/models"
+	"errors"
+	"strconv"
+	"time"
 
 	tele "gopkg.in/telebot.v3"
 
@@ -14,6 +18,13 @@ type Controller struct {
 	Bot      *tele.Bot
 	Provider *repositories.Provider
 	Logger   *zap.Logger
+	Delivery *delivery.Engine
+	// Commands is the command menu of every admin, set once the commands are registered.
+	Commands []tele.Command
+	// Approvers approve broadcasts of admins who are not masters, masters do if there are none.
+	Approvers []int64
+	// ApprovalTTL is how long a broadcast waits for an approval.
+	ApprovalTTL time.Duration
 }
 
 func (c *Controller) UpdateCache() error {
@@ -33,29 +44,194 @@ func (c *Controller) UpdateCache() error {
 		return err
 	}
 
+	if err := c.CreateMessageService().UpdateCache(); err != nil {
+		return err
+	}
+
+	if err := c.CreateScheduleService().UpdateCache(); err != nil {
+		return err
+	}
+
+	if err := c.CreateBroadcastService().UpdateCache(); err != nil {
+		return err
+	}
+
+	if err := c.CreateFailedDeliveryService().UpdateCache(); err != nil {
+		return err
+	}
+
+	if err := c.CreatePendingChatService().UpdateCache(); err != nil {
+		return err
+	}
+
+	if err := c.CreateApprovalService().UpdateCache(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (c *Controller) ClearUserState(user *models.User) {
-	user.State = ""
-	c.CreateUserService().Update(user)
+	c.SetUserState(user, "")
 }
 
+// SetUserState changes the state only, the user may be a copy older than the cached one.
 func (c *Controller) SetUserState(user *models.User, state string) {
 	user.State = state
-	c.CreateUserService().Update(user)
+	c.userService().SetState(user.Id, state)
 }
 
-func (c *Controller) SendText(chatId int64, text string) error {
-	_, err := c.Bot.Send(&tele.User{ID: chatId}, text, &tele.SendOptions{ParseMode: tele.ModeMarkdown})
+// Notify sends a plain text service message, e.g. a report to an admin.
+func (c *Controller) Notify(chatId int64, text string) error {
+	_, err := c.Bot.Send(&tele.User{ID: chatId}, text)
 	return err
 }
 
-func (c *Controller) SendPhotoByID(chatId int64, photoId string, caption string) error {
-	msg := &tele.Photo{File: tele.File{FileID: photoId}}
-	msg.Caption = caption
-	_, err := c.Bot.Send(&tele.User{ID: chatId}, msg, &tele.SendOptions{ParseMode: tele.ModeMarkdown})
-	return err
+// SetUserCommands shows the command menu in the chat of the admin.
+func (c *Controller) SetUserCommands(userId int64) error {
+	return c.Bot.SetCommands(c.Commands, tele.CommandScope{Type: tele.CommandScopeChat, ChatID: userId})
+}
+
+// ClearUserCommands removes the command menu from the chat of a removed admin.
+func (c *Controller) ClearUserCommands(userId int64) error {
+	return c.Bot.DeleteCommands(tele.CommandScope{Type: tele.CommandScopeChat, ChatID: userId})
+}
+
+// NotifyManagers sends a service message about chats to every admin who manages chats.
+func (c *Controller) NotifyManagers(text string, markup *tele.ReplyMarkup) {
+	users, err := c.CreateUserService().FindAll()
+	if err != nil {
+		c.Logger.Error("Failed to find admins", zap.Error(err))
+		return
+	}
+
+	for _, user := range users {
+		if !user.Can(models.PermissionManageChats) {
+			continue
+		}
+
+		if _, err := c.Bot.Send(&tele.User{ID: user.Id}, text, markup); err != nil {
+			c.Logger.Error("Failed to notify manager", zap.Int64("manager", user.Id), zap.Error(err))
+		}
+	}
+}
+
+// SendText sends the text formatted by the options, see models.Message.SendOptions.
+func (c *Controller) SendText(chatId int64, text string, opts *tele.SendOptions) (*tele.Message, error) {
+	return c.Bot.Send(&tele.User{ID: chatId}, text, opts)
+}
+
+// SendMessage sends the text alone, as a caption of the media
+// or as a caption of the first item of an album.
+func (c *Controller) SendMessage(chatId int64, media []models.Media, text string, opts *tele.SendOptions) ([]tele.Message, error) {
+	var msg *tele.Message
+	var err error
+
+	switch len(media) {
+	case 0:
+		msg, err = c.SendText(chatId, text, opts)
+	case 1:
+		msg, err = c.SendMedia(chatId, media[0], text, opts)
+	default:
+		return c.SendAlbum(chatId, media, text, opts)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []tele.Message{*msg}, nil
+}
+
+// SendMedia sends the file by its Telegram file id with the caption.
+func (c *Controller) SendMedia(chatId int64, media models.Media, caption string, opts *tele.SendOptions) (*tele.Message, error) {
+	var msg interface{}
+
+	if media.Type == models.VoiceMessage {
+		msg = &tele.Voice{File: tele.File{FileID: media.FileID}, Caption: caption}
+	} else {
+		input, err := inputMedia(media, caption)
+		if err != nil {
+			return nil, err
+		}
+		msg = input
+	}
+
+	return c.Bot.Send(&tele.User{ID: chatId}, msg, opts)
+}
+
+// SendAlbum sends the files as a media group, the caption is put on the first one.
+// Telegram does not allow buttons under albums, so the reply markup is not sent.
+func (c *Controller) SendAlbum(chatId int64, media []models.Media, caption string, opts *tele.SendOptions) ([]tele.Message, error) {
+	album := make(tele.Album, 0, len(media))
+
+	for i, item := range media {
+		if i > 0 {
+			caption = ""
+		}
+
+		input, err := inputMedia(item, caption)
+		if err != nil {
+			return nil, err
+		}
+
+		captioned := captionedMedia{Inputtable: input}
+		if i == 0 {
+			captioned.entities, captioned.parseMode = opts.Entities, opts.ParseMode
+		}
+		album = append(album, captioned)
+	}
+
+	return c.Bot.SendAlbum(&tele.User{ID: chatId}, album)
+}
+
+// captionedMedia formats the caption of a single album item,
+// telebot would apply the formatting of the send options to every item.
+type captionedMedia struct {
+	tele.Inputtable
+	entities  tele.Entities
+	parseMode tele.ParseMode
+}
+
+func (m captionedMedia) InputMedia() tele.InputMedia {
+	input := m.Inputtable.InputMedia()
+	input.Entities, input.ParseMode = m.entities, m.parseMode
+
+	return input
+}
+
+func inputMedia(media models.Media, caption string) (tele.Inputtable, error) {
+	file := tele.File{FileID: media.FileID}
+
+	switch media.Type {
+	case models.PhotoMessage:
+		return &tele.Photo{File: file, Caption: caption}, nil
+	case models.VideoMessage:
+		return &tele.Video{File: file, Caption: caption}, nil
+	case models.DocumentMessage:
+		return &tele.Document{File: file, Caption: caption}, nil
+	case models.AnimationMessage:
+		return &tele.Animation{File: file, Caption: caption}, nil
+	case models.AudioMessage:
+		return &tele.Audio{File: file, Caption: caption}, nil
+	default:
+		return nil, errors.New("unknown message type")
+	}
+}
+
+func (c *Controller) EditText(chatId int64, messageId int, text string, opts *tele.SendOptions) (*tele.Message, error) {
+	stored := tele.StoredMessage{MessageID: strconv.Itoa(messageId), ChatID: chatId}
+	return c.Bot.Edit(stored, text, opts)
+}
+
+func (c *Controller) EditCaption(chatId int64, messageId int, caption string, opts *tele.SendOptions) (*tele.Message, error) {
+	stored := tele.StoredMessage{MessageID: strconv.Itoa(messageId), ChatID: chatId}
+	return c.Bot.EditCaption(stored, caption, opts)
+}
+
+func (c *Controller) DeleteMessage(chatId int64, messageId int) error {
+	stored := tele.StoredMessage{MessageID: strconv.Itoa(messageId), ChatID: chatId}
+	return c.Bot.Delete(stored)
 }
 
 func (c *Controller) CreateLanguageService() IService[models.Language, uint64] {
@@ -78,13 +254,17 @@ func (c *Controller) CreateGroupService() IService[models.Group, uint64] {
 }
 
 func (c *Controller) CreateUserService() IService[models.User, int64] {
-	s := &UserService{
-		logger: c.Logger.With(zap.String("service", "UserService")),
-		repo:   c.Provider.CreateAdminsRepo(),
-		cache:  &cache.Users,
-	}
+	return c.userService()
+}
 
-	return s
+// userService is created apart from CreateUserService for the changes of the cached user IService lacks.
+func (c *Controller) userService() *UserService {
+	return &UserService{
+		logger:   c.Logger.With(zap.String("service", "UserService")),
+		repo:     c.Provider.CreateAdminsRepo(),
+		roleRepo: c.Provider.CreateRoleGrantRepo(),
+		cache:    &cache.Users,
+	}
 }
 func (c *Controller) CreateChatService() IService[models.Chat, int64] {
 	s := &ChatService{
@@ -96,6 +276,97 @@ func (c *Controller) CreateChatService() IService[models.Chat, int64] {
 	return s
 }
 
+func (c *Controller) CreateMessageService() IService[models.Message, uint64] {
+	s := &MessageService{
+		logger:   c.Logger.With(zap.String("service", "MessageService")),
+		provider: c.Provider,
+		repo:     c.Provider.CreateMessageRepo(),
+		cache:    &cache.Messages,
+	}
+
+	return s
+}
+
+func (c *Controller) CreateScheduleService() IService[models.Schedule, uint64] {
+	s := &ScheduleService{
+		logger: c.Logger.With(zap.String("service", "ScheduleService")),
+		repo:   c.Provider.CreateScheduleRepo(),
+		cache:  &cache.Schedules,
+	}
+
+	return s
+}
+
+func (c *Controller) CreateBroadcastService() IService[models.Broadcast, uint64] {
+	s := &BroadcastService{
+		logger: c.Logger.With(zap.String("service", "BroadcastService")),
+		repo:   c.Provider.CreateBroadcastRepo(),
+		cache:  &cache.Broadcasts,
+	}
+
+	return s
+}
+
+func (c *Controller) CreateFailedDeliveryService() IService[models.FailedDelivery, uint64] {
+	s := &FailedDeliveryService{
+		logger: c.Logger.With(zap.String("service", "FailedDeliveryService")),
+		repo:   c.Provider.CreateFailedDeliveryRepo(),
+		cache:  &cache.FailedDeliveries,
+	}
+
+	return s
+}
+
+func (c *Controller) CreateDeliveryService() IService[models.Delivery, uint64] {
+	s := &DeliveryService{
+		logger: c.Logger.With(zap.String("service", "DeliveryService")),
+		repo:   c.Provider.CreateDeliveryRepo(),
+	}
+
+	return s
+}
+
+func (c *Controller) CreatePendingChatService() IService[models.PendingChat, int64] {
+	s := &PendingChatService{
+		logger: c.Logger.With(zap.String("service", "PendingChatService")),
+		repo:   c.Provider.CreatePendingChatRepo(),
+		cache:  &cache.PendingChats,
+	}
+
+	return s
+}
+
+func (c *Controller) CreateApprovalService() IService[models.Approval, uint64] {
+	s := &ApprovalService{
+		logger: c.Logger.With(zap.String("service", "ApprovalService")),
+		repo:   c.Provider.CreateApprovalRepo(),
+		cache:  &cache.Approvals,
+	}
+
+	return s
+}
+
+func (c *Controller) CreateAuditService() IService[models.AuditEntry, uint64] {
+	return c.auditService()
+}
+
+// auditService is created apart from CreateAuditService for FindLatest, which IService lacks.
+func (c *Controller) auditService() *AuditService {
+	return &AuditService{
+		logger: c.Logger.With(zap.String("service", "AuditService")),
+		repo:   c.Provider.CreateAuditRepo(),
+	}
+}
+
+func (c *Controller) CreateRoleGrantService() IService[models.RoleGrant, uint64] {
+	s := &RoleGrantService{
+		logger: c.Logger.With(zap.String("service", "RoleGrantService")),
+		repo:   c.Provider.CreateRoleGrantRepo(),
+	}
+
+	return s
+}
+
 type IService[T any, ID comparable] interface {
 	ClearCache()
 	UpdateCache() error
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	db_models "DC_NewsSender/internal/db/models"
+	"DC_NewsSender/internal/db/repositories"
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/models"
+
+	"go.uber.org/zap"
+)
+
+// DeliveryService is not cached: every broadcast adds a record per chat.
+type DeliveryService struct {
+	logger *zap.Logger
+	repo   repositories.IRepository[db_models.Delivery, uint64]
+}
+
+func (s *DeliveryService) ClearCache() {}
+
+func (s *DeliveryService) UpdateCache() error {
+	return nil
+}
+
+func (s *DeliveryService) FindBy(selector string, values ...string) ([]models.Delivery, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindBy"),
+		zap.String("selector", selector),
+		zap.Strings("values", values),
+	)
+
+	logger.Debug("Finding delivery")
+
+	dbResults, err := s.repo.FindBy(selector, values...)
+	if err != nil {
+		logger.Error("Failed to find delivery in db", zap.Error(err))
+		return nil, err
+	}
+
+	var result []models.Delivery
+
+	for _, dbRes := range *dbResults {
+		result = append(result, models.Delivery(dbRes))
+	}
+
+	logger.Debug("Found delivery in db", zap.Any("delivery", result))
+
+	return result, nil
+}
+
+func (s *DeliveryService) FindByName(name string) (*models.Delivery, error) {
+	panic("not implemented")
+}
+
+func (s *DeliveryService) FindById(id uint64) (*models.Delivery, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindById"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Finding delivery")
+
+	dbResult, err := s.repo.FindById(id)
+	if err != nil {
+		logger.Error("Failed to find delivery in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found delivery in db", zap.Any("delivery", dbResult))
+
+	result := models.Delivery(*dbResult)
+
+	return &result, nil
+}
+
+func (s *DeliveryService) Add(delivery *models.Delivery) (*models.Delivery, error) {
+	logger := s.logger.With(
+		zap.String("function", "Add"),
+		zap.Any("delivery", delivery),
+	)
+
+	logger.Debug("Adding delivery")
+
+	dbDelivery := db_models.Delivery(*delivery)
+	dbResult, err := s.repo.Add(&dbDelivery)
+	if err != nil {
+		logger.Error("Failed to add delivery", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Added delivery", zap.Any("result", dbResult))
+
+	result := models.Delivery(*dbResult)
+
+	return &result, nil
+}
+
+func (s *DeliveryService) Update(delivery *models.Delivery) (*models.Delivery, error) {
+	logger := s.logger.With(
+		zap.String("function", "Update"),
+		zap.Any("delivery", delivery),
+	)
+
+	logger.Debug("Updating delivery")
+
+	dbDelivery := db_models.Delivery(*delivery)
+	result, err := s.repo.Update(&dbDelivery)
+	if err != nil {
+		logger.Error("Failed to update delivery", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Updated delivery", zap.Any("result", result))
+
+	return delivery, nil
+}
+
+func (s *DeliveryService) Remove(id uint64) error {
+	logger := s.logger.With(
+		zap.String("function", "Remove"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Removing delivery")
+
+	deliveryToDelete, _ := s.FindById(id)
+	if deliveryToDelete == nil {
+		err := constants.ErrNotFound
+		logger.Error("Failed to remove delivery", zap.Error(err))
+		return err
+	}
+
+	if err := s.repo.Remove(deliveryToDelete.Id); err != nil {
+		logger.Error("Failed to remove delivery", zap.Error(err))
+		return err
+	}
+
+	logger.Debug("Removed delivery")
+
+	return nil
+}
+
+func (s *DeliveryService) FindAll() ([]models.Delivery, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindAll"),
+	)
+
+	logger.Debug("Finding deliveries")
+
+	dbResults, err := s.repo.FindAll()
+	if err != nil {
+		logger.Error("Failed to find deliveries in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found deliveries in db")
+
+	result := make([]models.Delivery, 0, len(*dbResults))
+
+	for _, delivery := range *dbResults {
+		result = append(result, models.Delivery(delivery))
+	}
+
+	return result, nil
+}
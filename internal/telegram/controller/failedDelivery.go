@@ -0,0 +1,218 @@
+package controller
+
+import (
+	db_models "DC_NewsSender/internal/db/models"
+	"DC_NewsSender/internal/db/repositories"
+	"DC_NewsSender/internal/telegram/cache"
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/models"
+
+	"go.uber.org/zap"
+)
+
+type FailedDeliveryService struct {
+	cache  cache.ICache[uint64, models.FailedDelivery]
+	logger *zap.Logger
+	repo   repositories.IRepository[db_models.FailedDelivery, uint64]
+}
+
+func (s *FailedDeliveryService) ClearCache() {
+	logger := s.logger.With(
+		zap.String("function", "ClearCache"),
+	)
+
+	logger.Debug("Clearing cache")
+
+	s.cache.Clear()
+
+	logger.Debug("Cache cleared")
+}
+
+func (s *FailedDeliveryService) UpdateCache() error {
+	logger := s.logger.With(
+		zap.String("function", "UpdateCache"),
+	)
+
+	logger.Debug("Updating cache")
+
+	s.ClearCache()
+	results, err := s.findAllFromDb()
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		s.cache.Add(result.Id, result)
+	}
+
+	logger.Debug("Cache updated")
+
+	return nil
+}
+
+func (s *FailedDeliveryService) FindBy(selector string, values ...string) ([]models.FailedDelivery, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindBy"),
+		zap.String("selector", selector),
+		zap.Strings("values", values),
+	)
+
+	logger.Debug("Finding failed delivery")
+
+	dbResults, err := s.repo.FindBy(selector, values...)
+	if err != nil {
+		logger.Error("Failed to find failed delivery in db", zap.Error(err))
+		return nil, err
+	}
+
+	var result []models.FailedDelivery
+
+	for _, dbRes := range *dbResults {
+		result = append(result, models.FailedDelivery(dbRes))
+	}
+
+	logger.Debug("Found failed delivery in db", zap.Any("delivery", result))
+
+	return result, nil
+}
+
+func (s *FailedDeliveryService) FindByName(name string) (*models.FailedDelivery, error) {
+	panic("not implemented")
+}
+
+func (s *FailedDeliveryService) FindById(id uint64) (*models.FailedDelivery, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindById"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Finding failed delivery")
+
+	if result := s.cache.Find(id); result != nil {
+		logger.Debug("Found failed delivery in cache", zap.Any("delivery", result))
+		return result, nil
+	}
+
+	dbResult, err := s.repo.FindById(id)
+	if err != nil {
+		logger.Error("Failed to find failed delivery in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found failed delivery in db", zap.Any("delivery", dbResult))
+
+	s.cache.Add(dbResult.Id, models.FailedDelivery(*dbResult))
+
+	return s.cache.Find(id), nil
+}
+
+func (s *FailedDeliveryService) Add(delivery *models.FailedDelivery) (*models.FailedDelivery, error) {
+	logger := s.logger.With(
+		zap.String("function", "Add"),
+		zap.Any("delivery", delivery),
+	)
+
+	logger.Debug("Adding failed delivery")
+
+	dbFailedDelivery := db_models.FailedDelivery(*delivery)
+	dbResult, err := s.repo.Add(&dbFailedDelivery)
+	if err != nil {
+		logger.Error("Failed to add failed delivery", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Added failed delivery", zap.Any("result", dbResult))
+
+	result := models.FailedDelivery(*dbResult)
+
+	s.cache.Add(dbResult.Id, result)
+
+	return &result, nil
+}
+
+func (s *FailedDeliveryService) Update(delivery *models.FailedDelivery) (*models.FailedDelivery, error) {
+	logger := s.logger.With(
+		zap.String("function", "Update"),
+		zap.Any("delivery", delivery),
+	)
+
+	logger.Debug("Updating failed delivery")
+
+	dbFailedDelivery := db_models.FailedDelivery(*delivery)
+	result, err := s.repo.Update(&dbFailedDelivery)
+	if err != nil {
+		logger.Error("Failed to update failed delivery", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Updated failed delivery", zap.Any("result", result))
+
+	s.cache.Add(delivery.Id, *delivery)
+
+	return delivery, nil
+}
+
+func (s *FailedDeliveryService) Remove(id uint64) error {
+	logger := s.logger.With(
+		zap.String("function", "Remove"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Removing failed delivery")
+
+	deliveryToDelete, _ := s.FindById(id)
+	if deliveryToDelete == nil {
+		err := constants.ErrNotFound
+		logger.Error("Failed to remove failed delivery", zap.Error(err))
+		return err
+	}
+
+	if err := s.repo.Remove(deliveryToDelete.Id); err != nil {
+		logger.Error("Failed to remove failed delivery", zap.Error(err))
+		return err
+	}
+
+	logger.Debug("Removed failed delivery")
+
+	s.cache.Remove(deliveryToDelete.Id)
+
+	return nil
+}
+
+func (s *FailedDeliveryService) FindAll() ([]models.FailedDelivery, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindAll"),
+	)
+
+	logger.Debug("Finding failed deliveries")
+
+	result := s.cache.FindAll()
+
+	logger.Debug("Found failed deliveries in cache")
+
+	return result, nil
+}
+
+func (s *FailedDeliveryService) findAllFromDb() ([]models.FailedDelivery, error) {
+	logger := s.logger.With(
+		zap.String("function", "findAllFromDb"),
+	)
+
+	logger.Debug("Finding failed deliveries")
+
+	dbResults, err := s.repo.FindAll()
+	if err != nil {
+		logger.Error("Failed to find failed deliveries in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found failed deliveries in db", zap.Any("failed deliveries", dbResults))
+
+	result := make([]models.FailedDelivery, 0, len(*dbResults))
+
+	for _, delivery := range *dbResults {
+		result = append(result, models.FailedDelivery(delivery))
+	}
+
+	return result, nil
+}
@@ -160,7 +160,7 @@ func (s *LanguageService) Update(language *models.Language) (*models.Language, e
 
 	logger.Debug("Updated language", zap.Any("result", result))
 
-	s.cache.List.Store(language.Id, *language)
+	s.cache.Add(language.Name, *language)
 	return language, nil
 }
 
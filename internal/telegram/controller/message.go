@@ -0,0 +1,296 @@
+package controller
+
+import (
+	db_models "DC_NewsSender/internal/db/models"
+	"DC_NewsSender/internal/db/repositories"
+	"DC_NewsSender/internal/telegram/cache"
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/models"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+type MessageService struct {
+	cache    *cache.Cache[uint64, models.Message]
+	logger   *zap.Logger
+	provider *repositories.Provider
+	repo     repositories.IRepository[db_models.Message, uint64]
+}
+
+func (s *MessageService) ClearCache() {
+	logger := s.logger.With(
+		zap.String("function", "ClearCache"),
+	)
+
+	logger.Debug("Clearing cache")
+
+	s.cache.Clear()
+
+	logger.Debug("Cache cleared")
+}
+
+func (s *MessageService) UpdateCache() error {
+	logger := s.logger.With(
+		zap.String("function", "UpdateCache"),
+	)
+
+	logger.Debug("Updating cache")
+
+	s.ClearCache()
+	results, err := s.findAllFromDb()
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		s.cache.Add(result.Id, result)
+	}
+
+	logger.Debug("Cache updated")
+
+	return nil
+}
+
+func (s *MessageService) FindBy(selector string, values ...string) ([]models.Message, error) {
+	panic("not implemented")
+}
+
+func (s *MessageService) FindByName(name string) (*models.Message, error) {
+	panic("not implemented")
+}
+
+func (s *MessageService) FindById(id uint64) (*models.Message, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindById"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Finding message")
+
+	if result := s.cache.Find(id); result != nil {
+		logger.Debug("Found message in cache", zap.Any("message", result))
+		return result, nil
+	}
+
+	dbResult, err := s.repo.FindById(id)
+	if err != nil {
+		logger.Error("Failed to find message in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found message in db", zap.Any("message", dbResult))
+
+	s.cache.Add(dbResult.Id, messageFromDb(dbResult))
+
+	return s.cache.Find(id), nil
+}
+
+func (s *MessageService) Add(message *models.Message) (*models.Message, error) {
+	logger := s.logger.With(
+		zap.String("function", "Add"),
+		zap.Any("message", message),
+	)
+
+	logger.Debug("Adding message")
+
+	value, _ := s.FindById(message.Id)
+	if value != nil {
+		err := constants.ErrAlreadyExists
+		logger.Error("Failed to add message", zap.Error(err))
+		return nil, err
+	}
+
+	dbMessage := messageToDb(message)
+	dbResult, err := s.repo.Add(&dbMessage)
+	if err != nil {
+		logger.Error("Failed to add message", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Added message", zap.Any("result", dbResult))
+
+	result := messageFromDb(dbResult)
+
+	s.cache.Add(dbResult.Id, result)
+
+	return &result, nil
+}
+
+func (s *MessageService) Update(message *models.Message) (*models.Message, error) {
+	logger := s.logger.With(
+		zap.String("function", "Update"),
+		zap.Any("message", message),
+	)
+
+	logger.Debug("Updating message")
+
+	dbMessage := messageToDb(message)
+	variants, media := dbMessage.Variants, dbMessage.Media
+	dbMessage.Variants, dbMessage.Media = nil, nil
+
+	// The variants and the media are replaced as a whole, so the dropped languages are removed
+	// and a failure leaves the stored message as it was.
+	err := s.provider.Transaction(func(provider *repositories.Provider) error {
+		if _, err := provider.CreateMessageRepo().Update(&dbMessage); err != nil {
+			logger.Error("Failed to update message", zap.Error(err))
+			return err
+		}
+
+		variantRepo := provider.CreateMessageVariantRepo()
+		if err := variantRepo.RemoveBy("message_id = ?", fmt.Sprint(message.Id)); err != nil {
+			logger.Error("Failed to remove message variants", zap.Error(err))
+			return err
+		}
+
+		for i := range variants {
+			if _, err := variantRepo.Add(&variants[i]); err != nil {
+				logger.Error("Failed to add message variant", zap.Any("variant", variants[i]), zap.Error(err))
+				return err
+			}
+		}
+
+		mediaRepo := provider.CreateMessageMediaRepo()
+		if err := mediaRepo.RemoveBy("message_id = ?", fmt.Sprint(message.Id)); err != nil {
+			logger.Error("Failed to remove message media", zap.Error(err))
+			return err
+		}
+
+		for i := range media {
+			if _, err := mediaRepo.Add(&media[i]); err != nil {
+				logger.Error("Failed to add message media", zap.Any("media", media[i]), zap.Error(err))
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Updated message")
+
+	s.cache.Add(message.Id, *message)
+
+	return message, nil
+}
+
+func (s *MessageService) Remove(id uint64) error {
+	logger := s.logger.With(
+		zap.String("function", "Remove"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Removing message")
+
+	msgToDelete, _ := s.FindById(id)
+	if msgToDelete == nil {
+		err := constants.ErrNotFound
+		logger.Error("Failed to remove message", zap.Error(err))
+		return err
+	}
+
+	if err := s.repo.Remove(msgToDelete.Id); err != nil {
+		logger.Error("Failed to remove message", zap.Error(err))
+		return err
+	}
+
+	logger.Debug("Removed message")
+
+	s.cache.Remove(msgToDelete.Id)
+
+	return nil
+}
+
+func (s *MessageService) FindAll() ([]models.Message, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindAll"),
+	)
+
+	logger.Debug("Finding messages")
+
+	result := s.cache.FindAll()
+
+	logger.Debug("Found messages in cache")
+
+	return result, nil
+}
+
+func (s *MessageService) findAllFromDb() ([]models.Message, error) {
+	logger := s.logger.With(
+		zap.String("function", "findAllFromDb"),
+	)
+
+	logger.Debug("Finding messages")
+
+	dbResults, err := s.repo.FindAll()
+	if err != nil {
+		logger.Error("Failed to find messages in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found messages in db", zap.Any("messages", dbResults))
+
+	result := make([]models.Message, 0, len(*dbResults))
+
+	for i := range *dbResults {
+		result = append(result, messageFromDb(&(*dbResults)[i]))
+	}
+
+	return result, nil
+}
+
+func messageFromDb(dbMessage *db_models.Message) models.Message {
+	message := models.CreateMessage()
+	message.Id = dbMessage.Id
+	message.ParseMode = models.ParseMode(dbMessage.ParseMode)
+
+	media := append([]db_models.MessageMedia{}, dbMessage.Media...)
+	sort.Slice(media, func(i, j int) bool { return media[i].Position < media[j].Position })
+
+	for _, item := range media {
+		message.Media[item.LanguageId] = append(message.Media[item.LanguageId], models.Media{Type: models.MessageType(item.Type), FileID: item.FileId})
+	}
+
+	for _, variant := range dbMessage.Variants {
+		message.Text[variant.LanguageId] = variant.Text
+		message.Entities[variant.LanguageId] = variant.Entities
+		message.Buttons[variant.LanguageId] = variant.Buttons
+	}
+
+	return *message
+}
+
+func messageToDb(message *models.Message) db_models.Message {
+	dbMessage := db_models.Message{
+		Id:        message.Id,
+		ParseMode: string(message.ParseMode),
+		Variants:  make([]db_models.MessageVariant, 0, len(message.Text)),
+	}
+
+	for languageId, media := range message.Media {
+		for position, item := range media {
+			dbMessage.Media = append(dbMessage.Media, db_models.MessageMedia{
+				MessageId:  message.Id,
+				LanguageId: languageId,
+				Position:   position,
+				Type:       int(item.Type),
+				FileId:     item.FileID,
+			})
+		}
+	}
+
+	for languageId, text := range message.Text {
+		dbMessage.Variants = append(dbMessage.Variants, db_models.MessageVariant{
+			MessageId:  message.Id,
+			LanguageId: languageId,
+			Text:       text,
+			Entities:   message.Entities[languageId],
+			Buttons:    message.Buttons[languageId],
+		})
+	}
+
+	return dbMessage
+}
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	db_models "DC_NewsSender/internal/db/models"
+	"DC_NewsSender/internal/db/repositories"
+	"DC_NewsSender/internal/telegram/cache"
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/models"
+
+	"go.uber.org/zap"
+)
+
+type PendingChatService struct {
+	cache  cache.ICache[int64, models.PendingChat]
+	logger *zap.Logger
+	repo   repositories.IRepository[db_models.PendingChat, int64]
+}
+
+func (s *PendingChatService) ClearCache() {
+	logger := s.logger.With(
+		zap.String("function", "ClearCache"),
+	)
+
+	logger.Debug("Clearing cache")
+
+	s.cache.Clear()
+
+	logger.Debug("Cache cleared")
+}
+
+func (s *PendingChatService) UpdateCache() error {
+	logger := s.logger.With(
+		zap.String("function", "UpdateCache"),
+	)
+
+	logger.Debug("Updating cache")
+
+	s.ClearCache()
+	results, err := s.findAllFromDb()
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		s.cache.Add(result.Id, result)
+	}
+
+	logger.Debug("Cache updated")
+
+	return nil
+}
+
+func (s *PendingChatService) FindBy(selector string, values ...string) ([]models.PendingChat, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindBy"),
+		zap.String("selector", selector),
+		zap.Strings("values", values),
+	)
+
+	logger.Debug("Finding pending chat")
+
+	dbResults, err := s.repo.FindBy(selector, values...)
+	if err != nil {
+		logger.Error("Failed to find pending chat in db", zap.Error(err))
+		return nil, err
+	}
+
+	var result []models.PendingChat
+
+	for _, dbRes := range *dbResults {
+		result = append(result, models.PendingChat(dbRes))
+	}
+
+	logger.Debug("Found pending chat in db", zap.Any("chat", result))
+
+	return result, nil
+}
+
+func (s *PendingChatService) FindByName(name string) (*models.PendingChat, error) {
+	panic("not implemented")
+}
+
+func (s *PendingChatService) FindById(id int64) (*models.PendingChat, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindById"),
+		zap.Int64("id", id),
+	)
+
+	logger.Debug("Finding pending chat")
+
+	if result := s.cache.Find(id); result != nil {
+		logger.Debug("Found pending chat in cache", zap.Any("chat", result))
+		return result, nil
+	}
+
+	dbResult, err := s.repo.FindById(id)
+	if err != nil {
+		logger.Error("Failed to find pending chat in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found pending chat in db", zap.Any("chat", dbResult))
+
+	s.cache.Add(dbResult.Id, models.PendingChat(*dbResult))
+
+	return s.cache.Find(id), nil
+}
+
+func (s *PendingChatService) Add(chat *models.PendingChat) (*models.PendingChat, error) {
+	logger := s.logger.With(
+		zap.String("function", "Add"),
+		zap.Any("chat", chat),
+	)
+
+	logger.Debug("Adding pending chat")
+
+	dbPendingChat := db_models.PendingChat(*chat)
+	dbResult, err := s.repo.Add(&dbPendingChat)
+	if err != nil {
+		logger.Error("Failed to add pending chat", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Added pending chat", zap.Any("result", dbResult))
+
+	result := models.PendingChat(*dbResult)
+
+	s.cache.Add(dbResult.Id, result)
+
+	return &result, nil
+}
+
+func (s *PendingChatService) Update(chat *models.PendingChat) (*models.PendingChat, error) {
+	logger := s.logger.With(
+		zap.String("function", "Update"),
+		zap.Any("chat", chat),
+	)
+
+	logger.Debug("Updating pending chat")
+
+	dbPendingChat := db_models.PendingChat(*chat)
+	result, err := s.repo.Update(&dbPendingChat)
+	if err != nil {
+		logger.Error("Failed to update pending chat", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Updated pending chat", zap.Any("result", result))
+
+	s.cache.Add(chat.Id, *chat)
+
+	return chat, nil
+}
+
+func (s *PendingChatService) Remove(id int64) error {
+	logger := s.logger.With(
+		zap.String("function", "Remove"),
+		zap.Int64("id", id),
+	)
+
+	logger.Debug("Removing pending chat")
+
+	chatToDelete, _ := s.FindById(id)
+	if chatToDelete == nil {
+		err := constants.ErrNotFound
+		logger.Error("Failed to remove pending chat", zap.Error(err))
+		return err
+	}
+
+	if err := s.repo.Remove(chatToDelete.Id); err != nil {
+		logger.Error("Failed to remove pending chat", zap.Error(err))
+		return err
+	}
+
+	logger.Debug("Removed pending chat")
+
+	s.cache.Remove(chatToDelete.Id)
+
+	return nil
+}
+
+func (s *PendingChatService) FindAll() ([]models.PendingChat, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindAll"),
+	)
+
+	logger.Debug("Finding pending chats")
+
+	result := s.cache.FindAll()
+
+	logger.Debug("Found pending chats in cache")
+
+	return result, nil
+}
+
+func (s *PendingChatService) findAllFromDb() ([]models.PendingChat, error) {
+	logger := s.logger.With(
+		zap.String("function", "findAllFromDb"),
+	)
+
+	logger.Debug("Finding pending chats")
+
+	dbResults, err := s.repo.FindAll()
+	if err != nil {
+		logger.Error("Failed to find pending chats in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found pending chats in db", zap.Any("pending chats", dbResults))
+
+	result := make([]models.PendingChat, 0, len(*dbResults))
+
+	for _, chat := range *dbResults {
+		result = append(result, models.PendingChat(chat))
+	}
+
+	return result, nil
+}
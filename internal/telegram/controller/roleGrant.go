@@ -0,0 +1,241 @@
+package controller
+
+import (
+	db_models "DC_NewsSender/internal/db/models"
+	"DC_NewsSender/internal/db/repositories"
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/models"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// RoleGrantService is not cached: grants are cached with the user they belong to.
+type RoleGrantService struct {
+	logger *zap.Logger
+	repo   repositories.IRepository[db_models.RoleGrant, uint64]
+}
+
+func (s *RoleGrantService) ClearCache() {}
+
+func (s *RoleGrantService) UpdateCache() error {
+	return nil
+}
+
+func (s *RoleGrantService) FindBy(selector string, values ...string) ([]models.RoleGrant, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindBy"),
+		zap.String("selector", selector),
+		zap.Strings("values", values),
+	)
+
+	logger.Debug("Finding role grant")
+
+	dbResults, err := s.repo.FindBy(selector, values...)
+	if err != nil {
+		logger.Error("Failed to find role grant in db", zap.Error(err))
+		return nil, err
+	}
+
+	var result []models.RoleGrant
+
+	for _, dbRes := range *dbResults {
+		result = append(result, models.RoleGrant(dbRes))
+	}
+
+	logger.Debug("Found role grant in db", zap.Any("grants", result))
+
+	return result, nil
+}
+
+func (s *RoleGrantService) FindByName(name string) (*models.RoleGrant, error) {
+	panic("not implemented")
+}
+
+func (s *RoleGrantService) FindById(id uint64) (*models.RoleGrant, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindById"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Finding role grant")
+
+	dbResult, err := s.repo.FindById(id)
+	if err != nil {
+		logger.Error("Failed to find role grant in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found role grant in db", zap.Any("grant", dbResult))
+
+	result := models.RoleGrant(*dbResult)
+
+	return &result, nil
+}
+
+func (s *RoleGrantService) Add(grant *models.RoleGrant) (*models.RoleGrant, error) {
+	logger := s.logger.With(
+		zap.String("function", "Add"),
+		zap.Any("grant", grant),
+	)
+
+	logger.Debug("Adding role grant")
+
+	dbGrant := db_models.RoleGrant(*grant)
+	dbResult, err := s.repo.Add(&dbGrant)
+	if err != nil {
+		logger.Error("Failed to add role grant", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Added role grant", zap.Any("result", dbResult))
+
+	result := models.RoleGrant(*dbResult)
+
+	return &result, nil
+}
+
+func (s *RoleGrantService) Update(grant *models.RoleGrant) (*models.RoleGrant, error) {
+	logger := s.logger.With(
+		zap.String("function", "Update"),
+		zap.Any("grant", grant),
+	)
+
+	logger.Debug("Updating role grant")
+
+	dbGrant := db_models.RoleGrant(*grant)
+	result, err := s.repo.Update(&dbGrant)
+	if err != nil {
+		logger.Error("Failed to update role grant", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Updated role grant", zap.Any("result", result))
+
+	return grant, nil
+}
+
+func (s *RoleGrantService) Remove(id uint64) error {
+	logger := s.logger.With(
+		zap.String("function", "Remove"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Removing role grant")
+
+	grantToDelete, _ := s.FindById(id)
+	if grantToDelete == nil {
+		err := constants.ErrNotFound
+		logger.Error("Failed to remove role grant", zap.Error(err))
+		return err
+	}
+
+	if err := s.repo.Remove(grantToDelete.Id); err != nil {
+		logger.Error("Failed to remove role grant", zap.Error(err))
+		return err
+	}
+
+	logger.Debug("Removed role grant")
+
+	return nil
+}
+
+func (s *RoleGrantService) FindAll() ([]models.RoleGrant, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindAll"),
+	)
+
+	logger.Debug("Finding role grants")
+
+	dbResults, err := s.repo.FindAll()
+	if err != nil {
+		logger.Error("Failed to find role grants in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found role grants in db")
+
+	result := make([]models.RoleGrant, 0, len(*dbResults))
+
+	for _, grant := range *dbResults {
+		result = append(result, models.RoleGrant(grant))
+	}
+
+	return result, nil
+}
+
+// GrantRole gives the user the role, limited to the group unless groupId is 0.
+func (c *Controller) GrantRole(userId int64, role string, groupId uint64) (*models.User, error) {
+	user, err := c.findRoleUser(userId, role, groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, grant := range user.Roles {
+		if grant.Role == role && grant.GroupId == groupId {
+			return nil, constants.ErrAlreadyExists
+		}
+	}
+
+	grant, err := c.CreateRoleGrantService().Add(&models.RoleGrant{AdminId: user.Id, Role: role, GroupId: groupId})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.userService().AddRole(user.Id, db_models.RoleGrant(*grant))
+}
+
+// RevokeRole takes the role of the group from the user, groupId 0 is the role for every group.
+func (c *Controller) RevokeRole(userId int64, role string, groupId uint64) (*models.User, error) {
+	user, err := c.findRoleUser(userId, role, groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	var revoked *db_models.RoleGrant
+	for i, grant := range user.Roles {
+		if grant.Role == role && grant.GroupId == groupId {
+			revoked = &user.Roles[i]
+			break
+		}
+	}
+
+	if revoked == nil {
+		return nil, constants.ErrNotFound
+	}
+
+	if err := c.CreateRoleGrantService().Remove(revoked.Id); err != nil {
+		return nil, err
+	}
+
+	return c.userService().RemoveRole(user.Id, revoked.Id)
+}
+
+func (c *Controller) findRoleUser(userId int64, role string, groupId uint64) (*models.User, error) {
+	if _, ok := models.Roles[role]; !ok {
+		return nil, fmt.Errorf("unknown role %q, use %s", role, strings.Join(models.RoleNames(), ", "))
+	}
+
+	if groupId != 0 && !(models.RoleGrant{Role: role}).Grants(models.PermissionSend, 0) {
+		return nil, errors.New("only roles which send can be limited to a group")
+	}
+
+	if groupId != 0 {
+		if group, _ := c.CreateGroupService().FindById(groupId); group == nil {
+			return nil, errors.New("group not found")
+		}
+	}
+
+	user, _ := c.CreateUserService().FindById(userId)
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.IsMaster {
+		return nil, errors.New("masters have every permission")
+	}
+
+	return user, nil
+}
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	db_models "DC_NewsSender/internal/db/models"
+	"DC_NewsSender/internal/db/repositories"
+	"DC_NewsSender/internal/telegram/cache"
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/models"
+
+	"go.uber.org/zap"
+)
+
+type ScheduleService struct {
+	cache  cache.ICache[uint64, models.Schedule]
+	logger *zap.Logger
+	repo   repositories.IRepository[db_models.Schedule, uint64]
+}
+
+func (s *ScheduleService) ClearCache() {
+	logger := s.logger.With(
+		zap.String("function", "ClearCache"),
+	)
+
+	logger.Debug("Clearing cache")
+
+	s.cache.Clear()
+
+	logger.Debug("Cache cleared")
+}
+
+func (s *ScheduleService) UpdateCache() error {
+	logger := s.logger.With(
+		zap.String("function", "UpdateCache"),
+	)
+
+	logger.Debug("Updating cache")
+
+	s.ClearCache()
+	results, err := s.findAllFromDb()
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		s.cache.Add(result.Id, result)
+	}
+
+	logger.Debug("Cache updated")
+
+	return nil
+}
+
+func (s *ScheduleService) FindBy(selector string, values ...string) ([]models.Schedule, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindBy"),
+		zap.String("selector", selector),
+		zap.Strings("values", values),
+	)
+
+	logger.Debug("Finding schedule")
+
+	dbResults, err := s.repo.FindBy(selector, values...)
+	if err != nil {
+		logger.Error("Failed to find schedule in db", zap.Error(err))
+		return nil, err
+	}
+
+	var result []models.Schedule
+
+	for _, dbRes := range *dbResults {
+		result = append(result, models.Schedule(dbRes))
+	}
+
+	logger.Debug("Found schedule in db", zap.Any("schedule", result))
+
+	return result, nil
+}
+
+func (s *ScheduleService) FindByName(name string) (*models.Schedule, error) {
+	panic("not implemented")
+}
+
+func (s *ScheduleService) FindById(id uint64) (*models.Schedule, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindById"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Finding schedule")
+
+	if result := s.cache.Find(id); result != nil {
+		logger.Debug("Found schedule in cache", zap.Any("schedule", result))
+		return result, nil
+	}
+
+	dbResult, err := s.repo.FindById(id)
+	if err != nil {
+		logger.Error("Failed to find schedule in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found schedule in db", zap.Any("schedule", dbResult))
+
+	s.cache.Add(dbResult.Id, models.Schedule(*dbResult))
+
+	return s.cache.Find(id), nil
+}
+
+func (s *ScheduleService) Add(schedule *models.Schedule) (*models.Schedule, error) {
+	logger := s.logger.With(
+		zap.String("function", "Add"),
+		zap.Any("schedule", schedule),
+	)
+
+	logger.Debug("Adding schedule")
+
+	dbSchedule := db_models.Schedule(*schedule)
+	dbResult, err := s.repo.Add(&dbSchedule)
+	if err != nil {
+		logger.Error("Failed to add schedule", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Added schedule", zap.Any("result", dbResult))
+
+	result := models.Schedule(*dbResult)
+
+	s.cache.Add(dbResult.Id, result)
+
+	return &result, nil
+}
+
+func (s *ScheduleService) Update(schedule *models.Schedule) (*models.Schedule, error) {
+	logger := s.logger.With(
+		zap.String("function", "Update"),
+		zap.Any("schedule", schedule),
+	)
+
+	logger.Debug("Updating schedule")
+
+	dbSchedule := db_models.Schedule(*schedule)
+	result, err := s.repo.Update(&dbSchedule)
+	if err != nil {
+		logger.Error("Failed to update schedule", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Updated schedule", zap.Any("result", result))
+
+	s.cache.Add(schedule.Id, *schedule)
+
+	return schedule, nil
+}
+
+func (s *ScheduleService) Remove(id uint64) error {
+	logger := s.logger.With(
+		zap.String("function", "Remove"),
+		zap.Uint64("id", id),
+	)
+
+	logger.Debug("Removing schedule")
+
+	scheduleToDelete, _ := s.FindById(id)
+	if scheduleToDelete == nil {
+		err := constants.ErrNotFound
+		logger.Error("Failed to remove schedule", zap.Error(err))
+		return err
+	}
+
+	if err := s.repo.Remove(scheduleToDelete.Id); err != nil {
+		logger.Error("Failed to remove schedule", zap.Error(err))
+		return err
+	}
+
+	logger.Debug("Removed schedule")
+
+	s.cache.Remove(scheduleToDelete.Id)
+
+	return nil
+}
+
+func (s *ScheduleService) FindAll() ([]models.Schedule, error) {
+	logger := s.logger.With(
+		zap.String("function", "FindAll"),
+	)
+
+	logger.Debug("Finding schedules")
+
+	result := s.cache.FindAll()
+
+	logger.Debug("Found schedules in cache")
+
+	return result, nil
+}
+
+func (s *ScheduleService) findAllFromDb() ([]models.Schedule, error) {
+	logger := s.logger.With(
+		zap.String("function", "findAllFromDb"),
+	)
+
+	logger.Debug("Finding schedules")
+
+	dbResults, err := s.repo.FindAll()
+	if err != nil {
+		logger.Error("Failed to find schedules in db", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("Found schedules in db", zap.Any("schedules", dbResults))
+
+	result := make([]models.Schedule, 0, len(*dbResults))
+
+	for _, schedule := range *dbResults {
+		result = append(result, models.Schedule(schedule))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"DC_NewsSender/internal/telegram/models"
+	"DC_NewsSender/internal/telegram/templates"
+	"sort"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// templateData describes the chat to the placeholders of a message. Chats get
+// the group they were selected by, chats selected otherwise their first group.
+func (c *Controller) templateData(chat models.Chat, groupId uint64) templates.Data {
+	data := templates.Data{
+		ChatId:     chat.Id,
+		ChatName:   chat.Name,
+		LanguageId: chat.LanguageId,
+		Date:       time.Now(),
+		Variables:  chat.Variables,
+	}
+
+	if lang, _ := c.CreateLanguageService().FindById(chat.LanguageId); lang != nil {
+		data.LanguageName = lang.Name
+	}
+
+	if groupId == 0 {
+		for _, group := range chat.Groups {
+			if groupId == 0 || group.Id < groupId {
+				groupId = group.Id
+			}
+		}
+	}
+	if group, _ := c.CreateGroupService().FindById(groupId); group != nil {
+		data.GroupId, data.GroupName = group.Id, group.Name
+	}
+
+	return data
+}
+
+// renderMessage renders the language variant of the message for one chat.
+func (c *Controller) renderMessage(msg *models.Message, languageId uint64, data templates.Data) (string, *tele.SendOptions, error) {
+	opts := msg.SendOptions(languageId)
+
+	text, entities, err := templates.Render(msg.Text[languageId], msg.Entities[languageId], msg.ParseMode, data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if msg.ParseMode == models.ParseModeEntities {
+		opts.Entities = entities
+	}
+
+	return text, opts, nil
+}
+
+// RenderSample renders the language variant of the message for the first chat
+// of the language, or for sample values if there is no such chat.
+func (c *Controller) RenderSample(msg *models.Message, languageId uint64) (string, *tele.SendOptions, error) {
+	data := templates.SampleData()
+	data.LanguageId = languageId
+	if lang, _ := c.CreateLanguageService().FindById(languageId); lang != nil {
+		data.LanguageName = lang.Name
+	}
+
+	chats, _ := c.CreateChatService().FindAll()
+	sort.Slice(chats, func(i, j int) bool { return chats[i].Id < chats[j].Id })
+
+	for _, chat := range chats {
+		if chat.LanguageId == languageId {
+			data = c.templateData(chat, 0)
+			break
+		}
+	}
+
+	return c.renderMessage(msg, languageId, data)
+}
@@ -6,14 +6,21 @@ import (
 	"DC_NewsSender/internal/telegram/cache"
 	"DC_NewsSender/internal/telegram/constants"
 	"DC_NewsSender/internal/telegram/models"
+	"fmt"
+	"sync"
 
 	"go.uber.org/zap"
 )
 
+// userMutex makes changes of a cached user apply on top of each other,
+// callers hold copies which may be older than the cache.
+var userMutex sync.Mutex
+
 type UserService struct {
-	cache  *cache.Cache[int64, models.User]
-	logger *zap.Logger
-	repo   repositories.IRepository[db_models.Admin, int64]
+	cache    *cache.Cache[int64, models.User]
+	logger   *zap.Logger
+	repo     repositories.IRepository[db_models.Admin, int64]
+	roleRepo repositories.IRepository[db_models.RoleGrant, uint64]
 }
 
 func (s *UserService) ClearCache() {
@@ -124,7 +131,21 @@ func (s *UserService) Update(user *models.User) (*models.User, error) {
 
 	logger.Debug("Updating user")
 
-	result, err := s.repo.Update(&user.Admin)
+	userMutex.Lock()
+	defer userMutex.Unlock()
+
+	// A user removed meanwhile, e.g. by /removeadmin, stays removed.
+	current := s.cache.Find(user.Id)
+	if current == nil {
+		err := constants.ErrNotFound
+		logger.Warn("Failed to update user", zap.Error(err))
+		return nil, err
+	}
+
+	// The grants are changed through the role service, saving the associations
+	// of a stale user would bring revoked grants back.
+	admin := db_models.Admin{Id: user.Id, Name: user.Name, IsMaster: user.IsMaster}
+	result, err := s.repo.UpdateFields(&admin, "name", "is_master")
 	if err != nil {
 		logger.Error("Failed to update user", zap.Error(err))
 		return nil, err
@@ -132,9 +153,66 @@ func (s *UserService) Update(user *models.User) (*models.User, error) {
 
 	logger.Debug("Updated user", zap.Any("result", result))
 
-	s.cache.List.Store(user.Id, *user)
+	current.Name, current.IsMaster, current.State = user.Name, user.IsMaster, user.State
+	s.cache.Add(current.Id, *current)
+
+	return current, nil
+}
+
+// SetState changes the state of the cached user, the state is not stored in the db.
+func (s *UserService) SetState(userId int64, state string) error {
+	userMutex.Lock()
+	defer userMutex.Unlock()
+
+	current := s.cache.Find(userId)
+	if current == nil {
+		return constants.ErrNotFound
+	}
+
+	current.State = state
+	s.cache.Add(current.Id, *current)
+
+	return nil
+}
+
+// AddRole adds the grant stored in the db to the cached user.
+func (s *UserService) AddRole(userId int64, grant db_models.RoleGrant) (*models.User, error) {
+	userMutex.Lock()
+	defer userMutex.Unlock()
+
+	current := s.cache.Find(userId)
+	if current == nil {
+		return nil, constants.ErrNotFound
+	}
+
+	// Copies of the user share the grants, so they are copied before the change.
+	current.Roles = append(append([]db_models.RoleGrant{}, current.Roles...), grant)
+	s.cache.Add(current.Id, *current)
 
-	return user, nil
+	return current, nil
+}
+
+// RemoveRole removes the grant deleted from the db from the cached user.
+func (s *UserService) RemoveRole(userId int64, grantId uint64) (*models.User, error) {
+	userMutex.Lock()
+	defer userMutex.Unlock()
+
+	current := s.cache.Find(userId)
+	if current == nil {
+		return nil, constants.ErrNotFound
+	}
+
+	roles := make([]db_models.RoleGrant, 0, len(current.Roles))
+	for _, grant := range current.Roles {
+		if grant.Id != grantId {
+			roles = append(roles, grant)
+		}
+	}
+
+	current.Roles = roles
+	s.cache.Add(current.Id, *current)
+
+	return current, nil
 }
 
 func (s *UserService) Remove(id int64) error {
@@ -152,6 +230,11 @@ func (s *UserService) Remove(id int64) error {
 		return err
 	}
 
+	if err := s.roleRepo.RemoveBy("admin_id = ?", fmt.Sprint(usrToDelete.Id)); err != nil {
+		logger.Error("Failed to remove role grants of user", zap.Error(err))
+		return err
+	}
+
 	if err := s.repo.Remove(usrToDelete.Id); err != nil {
 		logger.Error("Failed to remove user", zap.Error(err))
 		return err
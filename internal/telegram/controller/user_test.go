@@ -0,0 +1,68 @@
+package controller
+
+import (
+	db_models "DC_NewsSender/internal/db/models"
+	"DC_NewsSender/internal/db/repositories"
+	"DC_NewsSender/internal/telegram/cache"
+	"DC_NewsSender/internal/telegram/models"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestSetUserStateKeepsRevokedRole(t *testing.T) {
+	cache.Users.Clear()
+	defer cache.Users.Clear()
+
+	c := &Controller{Logger: zap.NewNop(), Provider: repositories.CreateProvider(nil)}
+
+	cache.Users.Add(1, models.User{Admin: db_models.Admin{
+		Id:   1,
+		Name: "sender",
+		Roles: []db_models.RoleGrant{
+			{Id: 10, AdminId: 1, Role: models.RoleEditor},
+			{Id: 11, AdminId: 1, Role: models.RoleSender},
+		},
+	}})
+
+	// A handler holds the user while the grant is revoked.
+	stale, _ := c.CreateUserService().FindById(1)
+
+	if _, err := c.userService().RemoveRole(1, 11); err != nil {
+		t.Fatalf("RemoveRole() error = %v", err)
+	}
+
+	c.SetUserState(stale, "state")
+
+	user, _ := c.CreateUserService().FindById(1)
+	if user.Can(models.PermissionSend) {
+		t.Errorf("user can send after the sender role was revoked, roles = %v", user.Roles)
+	}
+	if !user.Can(models.PermissionStash) {
+		t.Errorf("user lost the editor role, roles = %v", user.Roles)
+	}
+	if user.State != "state" {
+		t.Errorf("user state = %q, want %q", user.State, "state")
+	}
+
+	c.ClearUserState(stale)
+
+	if user, _ := c.CreateUserService().FindById(1); user.State != "" || len(user.Roles) != 1 {
+		t.Errorf("user after ClearUserState = %+v, want no state and one role", user)
+	}
+}
+
+func TestSetUserStateKeepsRemovedUser(t *testing.T) {
+	cache.Users.Clear()
+	defer cache.Users.Clear()
+
+	c := &Controller{Logger: zap.NewNop(), Provider: repositories.CreateProvider(nil)}
+
+	stale := models.CreateUser(2, "removed")
+
+	c.SetUserState(stale, "state")
+
+	if user := cache.Users.Find(2); user != nil {
+		t.Errorf("removed user is back in the cache: %+v", user)
+	}
+}
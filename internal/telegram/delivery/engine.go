@@ -0,0 +1,248 @@
+package delivery
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	tele "gopkg.in/telebot.v3"
+)
+
+const (
+	// GlobalRate is how many messages per second the bot may send in total.
+	GlobalRate float64 = 25
+	// GlobalBurst is how many messages may be sent at once after a quiet period.
+	GlobalBurst int = 25
+	// ChatInterval is the minimal delay between two messages to the same chat.
+	ChatInterval time.Duration = 3 * time.Second
+	// Workers is the size of the worker pool.
+	Workers int = 8
+	// ProgressStep is how many deliveries happen between two progress reports.
+	ProgressStep int = 25
+	// MaxRetries is how many times a task is retried after a transient error.
+	MaxRetries int = 5
+	// RetryBackoff is the delay before the first retry, doubled for every next one.
+	RetryBackoff time.Duration = time.Second
+)
+
+// Task is a single delivery to a chat.
+type Task struct {
+	ChatId int64
+	Send   func() ([]tele.Message, error)
+}
+
+type Result struct {
+	Task     Task
+	Messages []tele.Message
+	Err      error
+}
+
+// Job is a set of tasks whose progress is reported as a whole.
+type Job struct {
+	Id    uint64
+	Tasks []Task
+	// OnResult is called as soon as a task is finished, so its outcome
+	// is not lost if the process stops before the whole job is done.
+	OnResult   func(result Result)
+	OnProgress func(done int, total int)
+	OnDone     func(results []Result)
+}
+
+// ErrCancelled is the result of the tasks of a cancelled job which were not delivered.
+var ErrCancelled = errors.New("delivery cancelled")
+
+type jobState struct {
+	mu        sync.Mutex
+	engine    *Engine
+	job       *Job
+	results   []Result
+	cancelled bool
+	// active counts the tasks being delivered, Cancel waits for them.
+	active sync.WaitGroup
+}
+
+type work struct {
+	task  Task
+	state *jobState
+}
+
+type Engine struct {
+	limiter *Limiter
+	queue   chan work
+	logger  *zap.Logger
+
+	mu sync.Mutex
+	// jobs holds the unfinished jobs by their id, several jobs may share one.
+	jobs map[uint64][]*jobState
+}
+
+// CreateEngine starts the worker pool.
+func CreateEngine(logger *zap.Logger) *Engine {
+	e := &Engine{
+		limiter: NewLimiter(GlobalRate, GlobalBurst, ChatInterval),
+		queue:   make(chan work, GlobalBurst*Workers),
+		logger:  logger.With(zap.String("service", "DeliveryEngine")),
+		jobs:    make(map[uint64][]*jobState),
+	}
+
+	for i := 0; i < Workers; i++ {
+		go e.worker()
+	}
+
+	return e
+}
+
+// Submit queues the job and returns immediately.
+func (e *Engine) Submit(job *Job) {
+	e.logger.Debug("Job submitted", zap.Uint64("job", job.Id), zap.Int("tasks", len(job.Tasks)))
+
+	state := &jobState{engine: e, job: job, results: make([]Result, 0, len(job.Tasks))}
+
+	if len(job.Tasks) == 0 {
+		if job.OnDone != nil {
+			go job.OnDone(state.results)
+		}
+		return
+	}
+
+	e.mu.Lock()
+	e.jobs[job.Id] = append(e.jobs[job.Id], state)
+	e.mu.Unlock()
+
+	go func() {
+		for _, task := range job.Tasks {
+			e.queue <- work{task: task, state: state}
+		}
+	}()
+}
+
+// Cancel stops the jobs with the id submitted so far: their queued tasks end with ErrCancelled.
+// It returns once the tasks being delivered are done, so their results are known.
+func (e *Engine) Cancel(id uint64) {
+	e.mu.Lock()
+	states := append([]*jobState(nil), e.jobs[id]...)
+	e.mu.Unlock()
+
+	for _, state := range states {
+		state.cancel()
+	}
+}
+
+func (e *Engine) worker() {
+	for w := range e.queue {
+		result := Result{Task: w.task, Err: ErrCancelled}
+
+		delivering := w.state.begin()
+		if delivering {
+			result.Messages, result.Err = e.deliver(w.task, w.state)
+		}
+
+		// The result is reported before Cancel stops waiting for the task.
+		if w.state.job.OnResult != nil {
+			w.state.job.OnResult(result)
+		}
+		if delivering {
+			w.state.active.Done()
+		}
+
+		w.state.complete(result)
+	}
+}
+
+// deliver sends the task honouring the limiter, retrying transient errors
+// with exponential backoff or after retry_after of flood errors.
+func (e *Engine) deliver(task Task, state *jobState) ([]tele.Message, error) {
+	backoff := RetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		e.limiter.Wait(task.ChatId)
+
+		if state.isCancelled() {
+			return nil, ErrCancelled
+		}
+
+		msgs, err := task.Send()
+		if !IsTransient(err) || attempt == MaxRetries {
+			return msgs, err
+		}
+
+		var floodErr tele.FloodError
+		if errors.As(err, &floodErr) {
+			e.logger.Warn("Flood limit reached", zap.Int64("chat", task.ChatId), zap.Int("retryAfter", floodErr.RetryAfter))
+			e.limiter.Pause(time.Duration(floodErr.RetryAfter) * time.Second)
+			continue
+		}
+
+		e.logger.Warn("Transient delivery error", zap.Int64("chat", task.ChatId), zap.Int("attempt", attempt+1), zap.Error(err))
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (s *jobState) complete(result Result) {
+	s.mu.Lock()
+	s.results = append(s.results, result)
+	done, total := len(s.results), len(s.job.Tasks)
+	s.mu.Unlock()
+
+	if done == total {
+		s.engine.forget(s)
+
+		if s.job.OnDone != nil {
+			s.job.OnDone(s.results)
+		}
+		return
+	}
+
+	if done%ProgressStep == 0 && s.job.OnProgress != nil {
+		s.job.OnProgress(done, total)
+	}
+}
+
+// begin marks the task as being delivered, false if the job is cancelled.
+func (s *jobState) begin() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancelled {
+		return false
+	}
+
+	s.active.Add(1)
+	return true
+}
+
+func (s *jobState) isCancelled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cancelled
+}
+
+func (s *jobState) cancel() {
+	s.mu.Lock()
+	s.cancelled = true
+	s.mu.Unlock()
+
+	s.active.Wait()
+}
+
+func (e *Engine) forget(state *jobState) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	states := e.jobs[state.job.Id]
+	for i := range states {
+		if states[i] == state {
+			states = append(states[:i], states[i+1:]...)
+			break
+		}
+	}
+
+	if len(states) == 0 {
+		delete(e.jobs, state.job.Id)
+	} else {
+		e.jobs[state.job.Id] = states
+	}
+}
@@ -0,0 +1,83 @@
+package delivery
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// telebot reports unknown API errors as plain "telegram: description (code)" errors.
+var codePattern = regexp.MustCompile(`\((\d{3})\)$`)
+
+// IsTransient reports whether a failed delivery is worth retrying:
+// network errors, 5xx responses and flood limits.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var floodErr tele.FloodError
+	if errors.As(err, &floodErr) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var teleErr *tele.Error
+	if errors.As(err, &teleErr) {
+		return isTransientCode(teleErr.Code)
+	}
+
+	if match := codePattern.FindStringSubmatch(err.Error()); match != nil {
+		code, _ := strconv.Atoi(match[1])
+		return isTransientCode(code)
+	}
+
+	return false
+}
+
+func isTransientCode(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// permanentErrors are descriptions of errors after which the chat is unreachable.
+var permanentErrors = []string{
+	"bot was kicked",
+	"chat not found",
+	"bot is not a member",
+}
+
+// IsPermanent reports whether the chat cannot receive messages anymore:
+// the bot was kicked, is not a member or the chat does not exist.
+func IsPermanent(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	description := strings.ToLower(err.Error())
+	for _, permanent := range permanentErrors {
+		if strings.Contains(description, permanent) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MigratedTo returns the id of the supergroup the group chat was upgraded to, or 0.
+func MigratedTo(err error) int64 {
+	var groupErr tele.GroupError
+	if errors.As(err, &groupErr) {
+		return groupErr.MigratedTo
+	}
+
+	return 0
+}
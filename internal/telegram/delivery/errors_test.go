@@ -0,0 +1,80 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"flood", tele.FloodError{RetryAfter: 5}, true},
+		{"server error", tele.NewError(502, "Bad Gateway"), true},
+		{"too many requests", tele.NewError(429, "Too Many Requests: retry later"), true},
+		{"wrapped server error", fmt.Errorf("send: %w", tele.NewError(500, "Internal Server Error")), true},
+		{"unknown api error", errors.New("telegram: Internal Server Error (500)"), true},
+		{"bad request", tele.ErrChatNotFound, false},
+		{"unknown bad request", errors.New("telegram: Bad Request: message is too long (400)"), false},
+		{"other", errors.New("file not found"), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := IsTransient(test.err); got != test.want {
+				t.Errorf("IsTransient(%v) = %v, want %v", test.err, got, test.want)
+			}
+		})
+	}
+}
+
+func TestIsPermanent(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"kicked from group", tele.ErrKickedFromGroup, true},
+		{"kicked from supergroup", tele.ErrKickedFromSuperGroup, true},
+		{"chat not found", tele.ErrChatNotFound, true},
+		{"not a member", errors.New("telegram: Forbidden: bot is not a member of the channel chat (403)"), true},
+		{"wrapped", fmt.Errorf("send: %w", tele.ErrChatNotFound), true},
+		{"server error", tele.NewError(502, "Bad Gateway"), false},
+		{"bad request", tele.NewError(400, "Bad Request: message text is empty"), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := IsPermanent(test.err); got != test.want {
+				t.Errorf("IsPermanent(%v) = %v, want %v", test.err, got, test.want)
+			}
+		})
+	}
+}
+
+func TestMigratedTo(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int64
+	}{
+		{"nil", nil, 0},
+		{"migrated", tele.GroupError{MigratedTo: -1001234567890}, -1001234567890},
+		{"other", tele.ErrChatNotFound, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := MigratedTo(test.err); got != test.want {
+				t.Errorf("MigratedTo(%s) = %d, want %d", test.name, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package delivery
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket shared by every worker combined with
+// a minimal interval between two deliveries to the same chat.
+type Limiter struct {
+	mu           sync.Mutex
+	rate         float64
+	burst        float64
+	tokens       float64
+	last         time.Time
+	chatInterval time.Duration
+	chatNext     map[int64]time.Time
+	pausedUntil  time.Time
+	// now and sleep are the clock of the limiter, replaced by a fake one in tests.
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+func NewLimiter(rate float64, burst int, chatInterval time.Duration) *Limiter {
+	return &Limiter{
+		rate:         rate,
+		burst:        float64(burst),
+		tokens:       float64(burst),
+		last:         time.Now(),
+		chatInterval: chatInterval,
+		chatNext:     make(map[int64]time.Time),
+		now:          time.Now,
+		sleep:        time.Sleep,
+	}
+}
+
+// Wait blocks until a message may be sent to the chat.
+func (l *Limiter) Wait(chatId int64) {
+	for {
+		delay := l.reserve(chatId)
+		if delay <= 0 {
+			return
+		}
+
+		l.sleep(delay)
+	}
+}
+
+// Pause stops every delivery for the given duration, e.g. after a 429 response.
+func (l *Limiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := l.now().Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// reserve takes a token for the chat or returns how long to wait before trying again.
+func (l *Limiter) reserve(chatId int64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if next, ok := l.chatNext[chatId]; ok && now.Before(next) {
+		return next.Sub(now)
+	}
+
+	if l.tokens < 1 {
+		return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	}
+
+	l.tokens--
+	l.chatNext[chatId] = now.Add(l.chatInterval)
+
+	for id, next := range l.chatNext {
+		if now.After(next) {
+			delete(l.chatNext, id)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,59 @@
+package delivery
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock moves on only when the limiter sleeps.
+type fakeClock struct {
+	now time.Time
+}
+
+func newTestLimiter(rate float64, burst int, chatInterval time.Duration) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, time.March, 14, 15, 0, 0, 0, time.UTC)}
+
+	l := NewLimiter(rate, burst, chatInterval)
+	l.last = clock.now
+	l.now = func() time.Time { return clock.now }
+	l.sleep = func(d time.Duration) { clock.now = clock.now.Add(d) }
+
+	return l, clock
+}
+
+func TestLimiterWait(t *testing.T) {
+	tests := []struct {
+		name         string
+		rate         float64
+		burst        int
+		chatInterval time.Duration
+		pause        time.Duration
+		chats        []int64
+		want         time.Duration
+	}{
+		{"within burst", 1, 3, 0, 0, []int64{1, 2, 3}, 0},
+		{"over burst", 1, 3, 0, 0, []int64{1, 2, 3, 4}, time.Second},
+		{"refilled at rate", 2, 1, 0, 0, []int64{1, 2, 3}, time.Second},
+		{"same chat", 100, 10, time.Second, 0, []int64{1, 1, 1}, 2 * time.Second},
+		{"other chats in between", 100, 10, time.Second, 0, []int64{1, 2, 3}, 0},
+		{"paused", 100, 10, 0, 5 * time.Second, []int64{1}, 5 * time.Second},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l, clock := newTestLimiter(test.rate, test.burst, test.chatInterval)
+			start := clock.now
+
+			if test.pause > 0 {
+				l.Pause(test.pause)
+			}
+			for _, chatId := range test.chats {
+				l.Wait(chatId)
+			}
+
+			if got := clock.now.Sub(start); got != test.want {
+				t.Errorf("waited %v, want %v", got, test.want)
+			}
+		})
+	}
+}
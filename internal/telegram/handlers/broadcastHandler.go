@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/controller"
+	"DC_NewsSender/internal/telegram/models"
+	"fmt"
+	"strconv"
+
+	tele "gopkg.in/telebot.v3"
+
+	"go.uber.org/zap"
+)
+
+type BroadcastHandler struct {
+	controller *controller.Controller
+}
+
+func CreateBroadcastHandler(controller *controller.Controller) *BroadcastHandler {
+	return &BroadcastHandler{controller: controller}
+}
+
+// HandleConfirm starts the broadcast the initiator has confirmed.
+func (h *BroadcastHandler) HandleConfirm(user *models.User, tctx tele.Context) {
+	logger := h.controller.Logger.With(
+		zap.String("function", "HandleConfirm"),
+		zap.Int64("user", user.Id),
+	)
+
+	logger.Debug("Handling broadcast confirmation", zap.String("data", tctx.Callback().Data))
+
+	response, err := h.controller.ConfirmBroadcast(user.Id, tctx.Callback().Data)
+	h.controller.Audit(user.Id, constants.CallbackBroadcastConfirm, map[string]string{"request_id": tctx.Callback().Data}, response, err)
+	if err != nil {
+		logger.Debug("Broadcast not confirmed", zap.Error(err))
+		tctx.Respond(&tele.CallbackResponse{Text: cmdError(err.Error())})
+		return
+	}
+
+	tctx.Edit(fmt.Sprintf("%s\n\n%s", tctx.Message().Text, response))
+	tctx.Respond()
+}
+
+// HandleCancel drops the broadcast the initiator has cancelled.
+func (h *BroadcastHandler) HandleCancel(user *models.User, tctx tele.Context) {
+	logger := h.controller.Logger.With(
+		zap.String("function", "HandleCancel"),
+		zap.Int64("user", user.Id),
+	)
+
+	logger.Debug("Handling broadcast cancellation", zap.String("data", tctx.Callback().Data))
+
+	err := h.controller.CancelBroadcast(user.Id, tctx.Callback().Data)
+	h.controller.Audit(user.Id, constants.CallbackBroadcastCancel, map[string]string{"request_id": tctx.Callback().Data}, "Broadcast cancelled.", err)
+	if err != nil {
+		logger.Debug("Broadcast not cancelled", zap.Error(err))
+		tctx.Respond(&tele.CallbackResponse{Text: cmdError(err.Error())})
+		return
+	}
+
+	tctx.Edit(fmt.Sprintf("%s\n\nBroadcast cancelled.", tctx.Message().Text))
+	tctx.Respond()
+}
+
+// HandleApprove starts the broadcast an approver has approved.
+func (h *BroadcastHandler) HandleApprove(user *models.User, tctx tele.Context) {
+	logger := h.controller.Logger.With(
+		zap.String("function", "HandleApprove"),
+		zap.Int64("user", user.Id),
+	)
+
+	logger.Debug("Handling broadcast approval", zap.String("data", tctx.Callback().Data))
+
+	approvalId, err := strconv.ParseUint(tctx.Callback().Data, 10, 64)
+	if err != nil {
+		tctx.Respond(&tele.CallbackResponse{Text: cmdError("invalid approval.")})
+		return
+	}
+
+	response, err := h.controller.ApproveBroadcast(user.Id, approvalId)
+	h.controller.Audit(user.Id, constants.CallbackBroadcastApprove, map[string]string{"approval_id": fmt.Sprint(approvalId)}, response, err)
+	if err != nil {
+		logger.Debug("Broadcast not approved", zap.Error(err))
+		tctx.Respond(&tele.CallbackResponse{Text: cmdError(err.Error())})
+		return
+	}
+
+	tctx.Edit(fmt.Sprintf("%s\n\n%s", tctx.Message().Text, response))
+	tctx.Respond()
+}
+
+// HandleReject drops the broadcast an approver has rejected.
+func (h *BroadcastHandler) HandleReject(user *models.User, tctx tele.Context) {
+	logger := h.controller.Logger.With(
+		zap.String("function", "HandleReject"),
+		zap.Int64("user", user.Id),
+	)
+
+	logger.Debug("Handling broadcast rejection", zap.String("data", tctx.Callback().Data))
+
+	approvalId, err := strconv.ParseUint(tctx.Callback().Data, 10, 64)
+	if err != nil {
+		tctx.Respond(&tele.CallbackResponse{Text: cmdError("invalid approval.")})
+		return
+	}
+
+	err = h.controller.RejectBroadcast(user.Id, approvalId)
+	h.controller.Audit(user.Id, constants.CallbackBroadcastReject, map[string]string{"approval_id": fmt.Sprint(approvalId)}, "Broadcast rejected.", err)
+	if err != nil {
+		logger.Debug("Broadcast not rejected", zap.Error(err))
+		tctx.Respond(&tele.CallbackResponse{Text: cmdError(err.Error())})
+		return
+	}
+
+	tctx.Edit(fmt.Sprintf("%s\n\nBroadcast rejected.", tctx.Message().Text))
+	tctx.Respond()
+}
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	db_models "DC_NewsSender/internal/db/models"
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/controller"
+	"DC_NewsSender/internal/telegram/models"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+
+	"go.uber.org/zap"
+)
+
+// keyboardRowSize is how many buttons a row of a picker keyboard holds.
+const keyboardRowSize = 2
+
+type ChatHandler struct {
+	controller *controller.Controller
+
+	// mu serializes registration, telegram reports an added bot by two updates.
+	mu sync.Mutex
+}
+
+func CreateChatHandler(controller *controller.Controller) *ChatHandler {
+	return &ChatHandler{controller: controller}
+}
+
+// HandleAdded registers the chat the bot has been added to as pending
+// and asks masters and managers to pick its language and group.
+func (h *ChatHandler) HandleAdded(tctx tele.Context) {
+	chat, sender := tctx.Chat(), tctx.Sender()
+	if chat == nil || sender == nil || chat.Type == tele.ChatPrivate {
+		return
+	}
+
+	if update := tctx.Update().MyChatMember; update != nil {
+		if !isMember(update.NewChatMember) || isMember(update.OldChatMember) {
+			return
+		}
+	}
+
+	logger := h.controller.Logger.With(
+		zap.String("function", "HandleAdded"),
+		zap.Int64("chat", chat.ID),
+		zap.Int64("sender", sender.ID),
+	)
+
+	logger.Debug("Handling bot added to chat")
+
+	if admin, _ := h.controller.CreateUserService().FindById(sender.ID); admin == nil {
+		logger.Warn("Bot added by unknown user")
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if existing, _ := h.controller.CreateChatService().FindById(chat.ID); existing != nil {
+		logger.Debug("Chat already registered")
+		return
+	}
+
+	var pendingChatService = h.controller.CreatePendingChatService()
+
+	if existing, _ := pendingChatService.FindById(chat.ID); existing != nil {
+		logger.Debug("Chat already pending")
+		return
+	}
+
+	pending, err := pendingChatService.Add(&models.PendingChat{
+		Id:        chat.ID,
+		Name:      chatName(chat),
+		AddedBy:   sender.ID,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		logger.Error("Failed to add pending chat", zap.Error(err))
+		return
+	}
+
+	languages, err := h.controller.CreateLanguageService().FindAll()
+	if err != nil {
+		logger.Error("Failed to find languages", zap.Error(err))
+		return
+	}
+	sort.Slice(languages, func(i, j int) bool { return languages[i].Id < languages[j].Id })
+
+	buttons := make([]tele.InlineButton, 0, len(languages))
+	for _, lang := range languages {
+		buttons = append(buttons, pickerButton(constants.CallbackChatLanguage, pending.Id, lang.Id, lang.Name))
+	}
+
+	h.controller.NotifyManagers(
+		fmt.Sprintf("Bot has been added to %s [%d] by %s [%d].\nPick the language of the chat:", pending.Name, pending.Id, sender.FirstName, sender.ID),
+		pickerKeyboard(buttons),
+	)
+
+	logger.Debug("Registered pending chat")
+}
+
+// HandleMigration follows a group chat upgraded to a supergroup.
+func (h *ChatHandler) HandleMigration(tctx tele.Context) {
+	from, to := tctx.Migration()
+
+	logger := h.controller.Logger.With(
+		zap.String("function", "HandleMigration"),
+		zap.Int64("from", from),
+		zap.Int64("to", to),
+	)
+
+	logger.Debug("Handling chat migration")
+
+	if err := h.controller.MigrateChat(from, to); err != nil {
+		logger.Debug("Chat not migrated", zap.Error(err))
+	}
+}
+
+// HandleLanguage stores the picked language of a pending chat and asks for its group.
+func (h *ChatHandler) HandleLanguage(user *models.User, tctx tele.Context) {
+	logger := h.controller.Logger.With(
+		zap.String("function", "HandleLanguage"),
+		zap.Int64("user", user.Id),
+	)
+
+	logger.Debug("Handling chat language", zap.String("data", tctx.Callback().Data))
+
+	pending, languageId, ok := h.findPending(user, tctx)
+	if !ok {
+		return
+	}
+
+	lang, _ := h.controller.CreateLanguageService().FindById(languageId)
+	if lang == nil {
+		tctx.Respond(&tele.CallbackResponse{Text: "Language not found."})
+		return
+	}
+
+	pending.LanguageId = lang.Id
+	if _, err := h.controller.CreatePendingChatService().Update(pending); err != nil {
+		logger.Error("Failed to update pending chat", zap.Error(err))
+		tctx.Respond(&tele.CallbackResponse{Text: "Failed to update the chat."})
+		return
+	}
+
+	groups, err := h.controller.CreateGroupService().FindAll()
+	if err != nil {
+		logger.Error("Failed to find groups", zap.Error(err))
+		return
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Id < groups[j].Id })
+
+	buttons := make([]tele.InlineButton, 0, len(groups))
+	for _, group := range groups {
+		buttons = append(buttons, pickerButton(constants.CallbackChatGroup, pending.Id, group.Id, group.Name))
+	}
+
+	tctx.Edit(
+		fmt.Sprintf("Chat %s [%d]\nLanguage: [%d] %s\nPick the group of the chat:", pending.Name, pending.Id, lang.Id, lang.Name),
+		pickerKeyboard(buttons),
+	)
+	tctx.Respond()
+}
+
+// HandleGroup activates the pending chat with the picked group.
+func (h *ChatHandler) HandleGroup(user *models.User, tctx tele.Context) {
+	logger := h.controller.Logger.With(
+		zap.String("function", "HandleGroup"),
+		zap.Int64("user", user.Id),
+	)
+
+	logger.Debug("Handling chat group", zap.String("data", tctx.Callback().Data))
+
+	pending, groupId, ok := h.findPending(user, tctx)
+	if !ok {
+		return
+	}
+
+	group, _ := h.controller.CreateGroupService().FindById(groupId)
+	if group == nil {
+		tctx.Respond(&tele.CallbackResponse{Text: "Group not found."})
+		return
+	}
+
+	lang, _ := h.controller.CreateLanguageService().FindById(pending.LanguageId)
+	if lang == nil {
+		tctx.Respond(&tele.CallbackResponse{Text: "Pick the language first."})
+		return
+	}
+
+	chat := &models.Chat{Id: pending.Id, Name: pending.Name, LanguageId: lang.Id, Groups: []db_models.Group{db_models.Group(*group)}, IsActive: true}
+	auditArguments := map[string]string{"chat_id": fmt.Sprint(chat.Id), "language_id": fmt.Sprint(lang.Id), "group_id": fmt.Sprint(group.Id)}
+	if _, err := h.controller.CreateChatService().Add(chat); err != nil {
+		logger.Error("Failed to add chat", zap.Error(err))
+		h.controller.Audit(user.Id, constants.CallbackChatGroup, auditArguments, "", err)
+		tctx.Respond(&tele.CallbackResponse{Text: "Failed to add the chat."})
+		return
+	}
+
+	if err := h.controller.CreatePendingChatService().Remove(pending.Id); err != nil {
+		logger.Error("Failed to remove pending chat", zap.Error(err))
+	}
+
+	response := fmt.Sprintf("Chat %s [%d] has been activated!\nLanguage: [%d] %s\nGroup: [%d] %s", chat.Name, chat.Id, lang.Id, lang.Name, group.Id, group.Name)
+	h.controller.Audit(user.Id, constants.CallbackChatGroup, auditArguments, response, nil)
+
+	tctx.Edit(response)
+	tctx.Respond()
+
+	logger.Debug("Activated chat", zap.Int64("chat", chat.Id))
+}
+
+// findPending parses the "chat_id:value_id" payload of a picker button
+// and finds the pending chat it refers to.
+func (h *ChatHandler) findPending(user *models.User, tctx tele.Context) (*models.PendingChat, uint64, bool) {
+	if !user.Can(models.PermissionManageChats) {
+		tctx.Respond(&tele.CallbackResponse{Text: "Only masters and managers can register chats."})
+		return nil, 0, false
+	}
+
+	chatPart, valuePart, _ := strings.Cut(tctx.Callback().Data, ":")
+
+	chatId, err := strconv.ParseInt(chatPart, 10, 64)
+	if err != nil {
+		tctx.Respond(&tele.CallbackResponse{Text: "Invalid chat."})
+		return nil, 0, false
+	}
+
+	valueId, err := strconv.ParseUint(valuePart, 10, 64)
+	if err != nil {
+		tctx.Respond(&tele.CallbackResponse{Text: "Invalid choice."})
+		return nil, 0, false
+	}
+
+	pending, _ := h.controller.CreatePendingChatService().FindById(chatId)
+	if pending == nil {
+		tctx.Edit(fmt.Sprintf("Chat [%d] is no longer pending.", chatId))
+		tctx.Respond()
+		return nil, 0, false
+	}
+
+	return pending, valueId, true
+}
+
+func pickerButton(unique string, chatId int64, valueId uint64, name string) tele.InlineButton {
+	return tele.InlineButton{
+		Unique: unique,
+		Text:   fmt.Sprintf("[%d] %s", valueId, name),
+		Data:   fmt.Sprintf("%d:%d", chatId, valueId),
+	}
+}
+
+func pickerKeyboard(buttons []tele.InlineButton) *tele.ReplyMarkup {
+	var rows [][]tele.InlineButton
+	for start := 0; start < len(buttons); start += keyboardRowSize {
+		end := start + keyboardRowSize
+		if end > len(buttons) {
+			end = len(buttons)
+		}
+		rows = append(rows, buttons[start:end])
+	}
+
+	return &tele.ReplyMarkup{InlineKeyboard: rows}
+}
+
+func isMember(member *tele.ChatMember) bool {
+	if member == nil {
+		return false
+	}
+
+	switch member.Role {
+	case tele.Creator, tele.Administrator, tele.Member:
+		return true
+	default:
+		return false
+	}
+}
+
+func chatName(chat *tele.Chat) string {
+	if chat.Title != "" {
+		return chat.Title
+	}
+
+	return chat.Username
+}
@@ -33,13 +33,15 @@ func (h *CommandHandler) SetCommands() error {
 
 	logger.Debug("Setting commands list", zap.Any("commands", cmds))
 
+	h.controller.Commands = cmds
+
 	users, err := h.controller.CreateUserService().FindAll()
 	if err != nil {
 		return err
 	}
 
 	for _, user := range users {
-		if err := h.controller.Bot.SetCommands(cmds, tele.CommandScope{Type: tele.CommandScopeChat, ChatID: user.Id}); err != nil {
+		if err := h.controller.SetUserCommands(user.Id); err != nil {
 			return err
 		}
 	}
@@ -83,7 +85,10 @@ func (h *CommandHandler) HandleCommand(user *models.User, tctx tele.Context) {
 				tctx.Send(fmt.Sprintf("Input %s", strings.Join(commandToExecute.Arguments.Names, ";")))
 				return
 			case nil:
-				tctx.Send(result)
+				// Commands which reply on their own, e.g. with a keyboard, return no result.
+				if result != "" {
+					tctx.Send(result)
+				}
 				h.controller.ClearUserState(user)
 				return
 			default:
@@ -1,26 +1,96 @@
 package handlers
 
 import (
-	"DC_NewsSender/internal/telegram/cache"
 	"DC_NewsSender/internal/telegram/commands"
+	"DC_NewsSender/internal/telegram/constants"
 	"DC_NewsSender/internal/telegram/controller"
+	"DC_NewsSender/internal/telegram/markup"
 	"DC_NewsSender/internal/telegram/models"
+	"DC_NewsSender/internal/telegram/templates"
 	"fmt"
+	"net/url"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
 
 	tele "gopkg.in/telebot.v3"
 
 	"go.uber.org/zap"
 )
 
+// messageTag is the ${msg_id;lang_id;options...} pattern of a stashed message.
+type messageTag struct {
+	msgId   string
+	langId  string
+	options []string
+}
+
+// tagOptions are the options of a message tag.
+type tagOptions struct {
+	sharedMedia bool
+	// parseMode is nil if the tag keeps the parse mode of the message.
+	parseMode *models.ParseMode
+}
+
+// tagOptionSharedMedia stores the attached files as the shared default of the message.
+const tagOptionSharedMedia = "default"
+
+func (t messageTag) parseOptions() (tagOptions, error) {
+	var result tagOptions
+
+	for _, option := range t.options {
+		option = strings.ToLower(strings.TrimSpace(option))
+
+		if option == tagOptionSharedMedia {
+			result.sharedMedia = true
+			continue
+		}
+
+		if mode, ok := findParseMode(option); ok {
+			result.parseMode = &mode
+			continue
+		}
+
+		return tagOptions{}, fmt.Errorf("unknown option \"%s\"", option)
+	}
+
+	return result, nil
+}
+
+func findParseMode(option string) (models.ParseMode, bool) {
+	for _, mode := range models.ParseModes {
+		if string(mode) == option {
+			return mode, true
+		}
+	}
+
+	return "", false
+}
+
 type MessageHandler struct {
 	controller *controller.Controller
+
+	mu     sync.Mutex
+	albums map[string]*album
+}
+
+// album collects the items of a media group until all of them have arrived.
+type album struct {
+	user     *models.User
+	tctx     tele.Context
+	messages []tele.Message
+	timer    *time.Timer
 }
 
 func CreateMessageHandler(controller *controller.Controller) *MessageHandler {
-	return &MessageHandler{controller: controller}
+	return &MessageHandler{
+		controller: controller,
+		albums:     make(map[string]*album),
+	}
 }
 
 func (h *MessageHandler) HandleMessage(user *models.User, tctx tele.Context) {
@@ -32,21 +102,105 @@ func (h *MessageHandler) HandleMessage(user *models.User, tctx tele.Context) {
 
 	logger.Debug("Handling message")
 
-	msgId, langId, text, photo := h.parseMessage(*tctx.Message())
+	if tctx.Message().AlbumID != "" {
+		h.collectAlbum(user, tctx)
+		return
+	}
 
-	if text == "" {
+	tag, text, entities, media, found := h.parseMessage(*tctx.Message())
+
+	if !found {
+		return
+	}
+
+	var items []models.Media
+	if media.FileID != "" {
+		items = append(items, media)
+	}
+
+	h.stash(user, tctx, tag, text, entities, items)
+}
+
+// collectAlbum buffers an album item and stashes the album once no more items arrive.
+func (h *MessageHandler) collectAlbum(user *models.User, tctx tele.Context) {
+	albumId := tctx.Message().AlbumID
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	item, ok := h.albums[albumId]
+	if !ok {
+		item = &album{user: user, tctx: tctx}
+		item.timer = time.AfterFunc(constants.AlbumCollectDelay, func() { h.flushAlbum(albumId) })
+		h.albums[albumId] = item
+	} else {
+		item.timer.Reset(constants.AlbumCollectDelay)
+	}
+
+	item.messages = append(item.messages, *tctx.Message())
+}
+
+func (h *MessageHandler) flushAlbum(albumId string) {
+	h.mu.Lock()
+	item, ok := h.albums[albumId]
+	delete(h.albums, albumId)
+	h.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	logger := h.controller.Logger.With(
+		zap.String("function", "flushAlbum"),
+		zap.Any("user", item.user.Id),
+		zap.String("album", albumId),
+	)
+
+	logger.Debug("Handling album", zap.Int("items", len(item.messages)))
+
+	sort.Slice(item.messages, func(i, j int) bool { return item.messages[i].ID < item.messages[j].ID })
+
+	var tag messageTag
+	var text string
+	var entities tele.Entities
+	var media []models.Media
+	var found bool
+	for _, message := range item.messages {
+		media = append(media, parseMedia(message))
+
+		if !found {
+			tag, text, entities, _, found = h.parseMessage(message)
+		}
+	}
+
+	if !found {
 		return
 	}
 
+	h.stash(item.user, item.tctx, tag, text, entities, media)
+}
+
+// stash saves a language variant of the message together with its media.
+func (h *MessageHandler) stash(user *models.User, tctx tele.Context, tag messageTag, text string, entities tele.Entities, media []models.Media) {
+	logger := h.controller.Logger.With(
+		zap.String("function", "stash"),
+		zap.Any("user", user.Id),
+	)
+
 	h.controller.ClearUserState(user)
 
-	messageId, err := strconv.ParseUint(msgId, 10, 64)
+	if !user.Can(models.PermissionStash) {
+		tctx.Send(cmdError("%s to stash messages.", constants.ErrNoPermission))
+		return
+	}
+
+	messageId, err := strconv.ParseUint(tag.msgId, 10, 64)
 	if err != nil {
 		tctx.Send(cmdError("invalid message id.\nMust be positive number."))
 		return
 	}
 
-	languageId, err := strconv.ParseUint(langId, 10, 64)
+	languageId, err := strconv.ParseUint(tag.langId, 10, 64)
 	if err != nil {
 		tctx.Send(cmdError("invalid language id.\nUse /%s to verify.", commands.LanguageGroup.List))
 		return
@@ -58,24 +212,130 @@ func (h *MessageHandler) HandleMessage(user *models.User, tctx tele.Context) {
 		return
 	}
 
-	msg := cache.Messages.Find(messageId)
+	options, err := tag.parseOptions()
+	if err != nil {
+		tctx.Send(cmdError("%s.\nAvailable options: %s, %s.", err, tagOptionSharedMedia, parseModeNames()))
+		return
+	}
+
+	text, entities, buttons, err := parseButtons(text, entities)
+	if err != nil {
+		tctx.Send(cmdError("%s.", err))
+		return
+	}
 
-	if msg == nil {
+	var messageService = h.controller.CreateMessageService()
+
+	// The cached message is shared, the variant is stashed into a copy of it
+	// which replaces the cached one only once it is valid and saved.
+	msg, _ := messageService.FindById(messageId)
+
+	isNew := msg == nil
+	if isNew {
 		msg = models.CreateMessage()
+	} else {
+		msg = msg.Clone()
+	}
+
+	parseMode := msg.ParseMode
+	if options.parseMode != nil {
+		parseMode = *options.parseMode
+	}
+
+	if err := templates.Validate(text); err != nil {
+		tctx.Send(cmdError("text has an invalid placeholder: %s.", err))
+		return
+	}
+	if err := markup.Validate(parseMode, sampleText(parseMode, text)); err != nil {
+		tctx.Send(cmdError("text is not valid %s: %s.", parseMode, err))
+		return
+	}
+	if parseMode != msg.ParseMode {
+		for variantLanguageId, variantText := range msg.Text {
+			if variantLanguageId == languageId {
+				continue
+			}
+			if err := markup.Validate(parseMode, sampleText(parseMode, variantText)); err != nil {
+				tctx.Send(cmdError("text of language [%d] is not valid %s: %s.\nUpdate it before changing the parse mode.", variantLanguageId, parseMode, err))
+				return
+			}
+		}
 	}
 
 	msg.Id = messageId
+	msg.ParseMode = parseMode
 	msg.Text[languageId] = text
-	if photo != "" {
-		msg.Photo = photo
+	mediaKey := languageId
+	if options.sharedMedia {
+		mediaKey = models.SharedMedia
+	}
+
+	languageMedia := msg.MediaFor(languageId)
+	if len(media) > 0 && (mediaKey == languageId || msg.Media[languageId] == nil) {
+		languageMedia = media
+	}
+	if strings.TrimSpace(text) == "" && len(languageMedia) == 0 {
+		tctx.Send(cmdError("message text is empty."))
+		return
+	}
+	if len(buttons) > 0 && len(languageMedia) > 1 {
+		tctx.Send(cmdError("albums cannot have buttons."))
+		return
 	}
 
-	cache.Messages.Add(msg.Id, *msg)
+	msg.Entities[languageId] = entities
+	msg.Buttons[languageId] = buttons
+	if len(media) > 0 {
+		msg.Media[mediaKey] = media
+	}
 
-	tctx.Send(fmt.Sprintf("Message stashed\nMessage ID: %d\nLanguage: [%d] %s", msg.Id, lang.Id, lang.Name))
+	if isNew {
+		_, err = messageService.Add(msg)
+	} else {
+		_, err = messageService.Update(msg)
+	}
+	auditArguments := map[string]string{
+		"message_id":  fmt.Sprint(msg.Id),
+		"language_id": fmt.Sprint(lang.Id),
+		"parse_mode":  fmt.Sprint(msg.ParseMode),
+		"media":       fmt.Sprint(len(media)),
+	}
+	if err != nil {
+		logger.Error("Failed to stash message", zap.Error(err))
+		h.controller.Audit(user.Id, constants.AuditStash, auditArguments, "", err)
+		tctx.Send(cmdError("failed to stash message [%d].", messageId))
+		return
+	}
+
+	response := fmt.Sprintf("Message stashed\nMessage ID: %d\nLanguage: [%d] %s\nParse mode: %s", msg.Id, lang.Id, lang.Name, msg.ParseMode)
+	if len(buttons) > 0 {
+		response += fmt.Sprintf("\nButtons: %d row(s)", len(buttons))
+	}
+	if len(media) > 0 {
+		target := "language"
+		if mediaKey == models.SharedMedia {
+			target = "shared default"
+		}
+		response += fmt.Sprintf("\nMedia: %d file(s), %s", len(media), target)
+	}
+
+	h.controller.Audit(user.Id, constants.AuditStash, auditArguments, response, nil)
+
+	tctx.Send(response)
+}
+
+func parseModeNames() string {
+	names := make([]string, 0, len(models.ParseModes))
+	for _, mode := range models.ParseModes {
+		names = append(names, string(mode))
+	}
+
+	return strings.Join(names, ", ")
 }
 
-func (h *MessageHandler) parseMessage(msg tele.Message) (string, string, string, string) {
+// parseMessage cuts the tag from the text or caption of the message, found reports
+// whether it has one: a caption of only the tag is a valid media message with no text.
+func (h *MessageHandler) parseMessage(msg tele.Message) (tag messageTag, text string, entities tele.Entities, media models.Media, found bool) {
 	logger := h.controller.Logger.With(
 		zap.String("function", "parseMessage"),
 		zap.Any("message", msg.ID),
@@ -83,42 +343,143 @@ func (h *MessageHandler) parseMessage(msg tele.Message) (string, string, string,
 
 	logger.Debug("Parsing message")
 
-	if msg.Text == "" && msg.Photo == nil {
-		return "", "", "", ""
+	text, entities = msg.Text, msg.Entities
+	media = parseMedia(msg)
+	if media.FileID != "" {
+		text, entities = msg.Caption, msg.CaptionEntities
 	}
 
-	var text string
-	var photo string
-
-	if msg.Photo != nil {
-		text = msg.Caption
-		photo = msg.Photo.FileID
-	} else {
-		text = msg.Text
+	if text == "" {
+		return messageTag{}, "", nil, models.Media{}, false
 	}
 
 	const pattern = `\${(.+?)}`
-	const submatchIndex = 1
 
 	regex := regexp.MustCompile(pattern)
-	match := regex.FindStringSubmatch(text)
+	match := regex.FindStringSubmatchIndex(text)
 	if match == nil {
-		return "", "", "", ""
+		return messageTag{}, "", nil, models.Media{}, false
 	}
 
-	params := strings.Split(match[submatchIndex], ";")
+	params := strings.Split(text[match[2]:match[3]], ";")
 	if len(params) < 2 {
-		return "", "", "", ""
+		return messageTag{}, "", nil, models.Media{}, false
 	}
 
-	msgId := params[0]
-	langId := params[1]
+	tag = messageTag{msgId: params[0], langId: params[1], options: params[2:]}
+
+	entities = cutEntities(entities, utf16Len(text[:match[0]]), utf16Len(text[match[0]:match[1]]))
+	text = text[:match[0]] + text[match[1]:]
+
+	logger.Debug("Parsed message", zap.Any("value", []string{tag.msgId, tag.langId, text}), zap.Strings("options", tag.options), zap.Any("media", media))
+
+	return tag, text, entities, media, true
+}
+
+var buttonPattern = regexp.MustCompile(`\[\[([^|\]]+)\|([^\]]+)\]\]`)
+
+// parseButtons cuts the trailing lines of [[Label|URL]] buttons from the text.
+// Every line is a row of the inline keyboard.
+func parseButtons(text string, entities tele.Entities) (string, tele.Entities, [][]tele.InlineButton, error) {
+	lines := strings.Split(text, "\n")
 
-	if match[submatchIndex] != "" {
-		text = strings.Replace(text, match[0], "", 1)
+	end := len(lines)
+	for end > 0 {
+		line := strings.TrimSpace(lines[end-1])
+		if line != "" && strings.TrimSpace(buttonPattern.ReplaceAllString(line, "")) != "" {
+			break
+		}
+		end--
 	}
 
-	logger.Debug("Parsed message", zap.Any("value", []string{msgId, langId, text, photo}))
+	var rows [][]tele.InlineButton
+	for _, line := range lines[end:] {
+		var row []tele.InlineButton
+		for _, match := range buttonPattern.FindAllStringSubmatch(line, -1) {
+			label, link := strings.TrimSpace(match[1]), strings.TrimSpace(match[2])
 
-	return msgId, langId, text, photo
+			target, err := url.Parse(link)
+			if err != nil || (target.Scheme != "https" && target.Scheme != "http" && target.Scheme != "tg") {
+				return "", nil, nil, fmt.Errorf("invalid url \"%s\" of button \"%s\"", link, label)
+			}
+
+			row = append(row, tele.InlineButton{Text: label, URL: link})
+		}
+
+		if len(row) > 0 {
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 0 {
+		return text, entities, nil, nil
+	}
+
+	kept := strings.Join(lines[:end], "\n")
+	entities = cutEntities(entities, utf16Len(kept), utf16Len(text)-utf16Len(kept))
+
+	return kept, entities, rows, nil
+}
+
+// cutEntities removes the range of the text from the entities.
+// Offsets and lengths are in UTF-16 code units, as Telegram counts them.
+func cutEntities(entities tele.Entities, offset, length int) tele.Entities {
+	shift := func(position int) int {
+		switch {
+		case position <= offset:
+			return position
+		case position < offset+length:
+			return offset
+		default:
+			return position - length
+		}
+	}
+
+	result := make(tele.Entities, 0, len(entities))
+	for _, entity := range entities {
+		start, end := shift(entity.Offset), shift(entity.Offset+entity.Length)
+		if end <= start {
+			continue
+		}
+
+		entity.Offset, entity.Length = start, end-start
+		result = append(result, entity)
+	}
+
+	return result
+}
+
+// sampleText renders the placeholders of a valid text, their values are checked
+// against the parse mode instead of the placeholders themselves.
+func sampleText(mode models.ParseMode, text string) string {
+	rendered, _, err := templates.Render(text, nil, mode, templates.SampleData())
+	if err != nil {
+		return text
+	}
+
+	return rendered
+}
+
+func utf16Len(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
+
+// parseMedia returns the file attached to the message, if any.
+func parseMedia(msg tele.Message) models.Media {
+	switch {
+	case msg.Photo != nil:
+		return models.Media{Type: models.PhotoMessage, FileID: msg.Photo.FileID}
+	case msg.Video != nil:
+		return models.Media{Type: models.VideoMessage, FileID: msg.Video.FileID}
+	case msg.Animation != nil:
+		return models.Media{Type: models.AnimationMessage, FileID: msg.Animation.FileID}
+	case msg.Document != nil:
+		return models.Media{Type: models.DocumentMessage, FileID: msg.Document.FileID}
+	case msg.Audio != nil:
+		return models.Media{Type: models.AudioMessage, FileID: msg.Audio.FileID}
+	case msg.Voice != nil:
+		return models.Media{Type: models.VoiceMessage, FileID: msg.Voice.FileID}
+	default:
+		return models.Media{}
+	}
 }
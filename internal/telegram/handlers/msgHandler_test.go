@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func TestCutEntities(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		tag      string
+		entities tele.Entities
+		want     tele.Entities
+	}{
+		{
+			name: "tag at start",
+			text: "${1;2}Hello", tag: "${1;2}",
+			entities: tele.Entities{{Offset: 6, Length: 5}, {Offset: 0, Length: 11}},
+			want:     tele.Entities{{Offset: 0, Length: 5}, {Offset: 0, Length: 5}},
+		},
+		{
+			name: "tag at end",
+			text: "Hello ${1;2}", tag: "${1;2}",
+			entities: tele.Entities{{Offset: 0, Length: 5}, {Offset: 0, Length: 12}},
+			want:     tele.Entities{{Offset: 0, Length: 5}, {Offset: 0, Length: 6}},
+		},
+		{
+			name: "tag in the middle",
+			text: "Hi ${1;2} there", tag: "${1;2}",
+			entities: tele.Entities{{Offset: 10, Length: 5}, {Offset: 0, Length: 15}, {Offset: 0, Length: 2}},
+			want:     tele.Entities{{Offset: 4, Length: 5}, {Offset: 0, Length: 9}, {Offset: 0, Length: 2}},
+		},
+		{
+			name: "entity overlapping tag start",
+			text: "Hi ${1;2} there", tag: "${1;2}",
+			entities: tele.Entities{{Offset: 1, Length: 4}},
+			want:     tele.Entities{{Offset: 1, Length: 2}},
+		},
+		{
+			name: "entity overlapping tag end",
+			text: "Hi ${1;2} there", tag: "${1;2}",
+			entities: tele.Entities{{Offset: 5, Length: 7}},
+			want:     tele.Entities{{Offset: 3, Length: 3}},
+		},
+		{
+			name: "entity of the tag only",
+			text: "Hi ${1;2} there", tag: "${1;2}",
+			entities: tele.Entities{{Offset: 3, Length: 6}, {Offset: 4, Length: 2}},
+			want:     tele.Entities{},
+		},
+		{
+			name: "surrogate pair before tag",
+			text: "😀${1;2}x", tag: "${1;2}",
+			entities: tele.Entities{{Offset: 0, Length: 2}, {Offset: 8, Length: 1}},
+			want:     tele.Entities{{Offset: 0, Length: 2}, {Offset: 2, Length: 1}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			index := strings.Index(test.text, test.tag)
+			if index < 0 {
+				t.Fatalf("tag %q not in %q", test.tag, test.text)
+			}
+
+			got := cutEntities(test.entities, utf16Len(test.text[:index]), utf16Len(test.tag))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("cutEntities(%v) = %v, want %v", test.entities, got, test.want)
+			}
+		})
+	}
+}
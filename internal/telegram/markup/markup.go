@@ -0,0 +1,265 @@
+package markup
+
+import (
+	"DC_NewsSender/internal/telegram/models"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validate checks the text against the escaping rules of the parse mode,
+// so Telegram does not reject it while it is being broadcast.
+func Validate(mode models.ParseMode, text string) error {
+	switch mode {
+	case models.ParseModeHTML:
+		return validateHTML(text)
+	case models.ParseModeMarkdown:
+		return validateMarkdown(text)
+	case models.ParseModeMarkdownV2:
+		return validateMarkdownV2(text)
+	default:
+		return nil
+	}
+}
+
+// Escape makes the value show up literally in a text of the parse mode.
+func Escape(mode models.ParseMode, value string) string {
+	switch mode {
+	case models.ParseModeHTML:
+		return html.EscapeString(value)
+	case models.ParseModeMarkdown:
+		return escapeWith(value, "_*`[")
+	case models.ParseModeMarkdownV2:
+		return escapeWith(value, "_*[]()~`>#+-=|{}.!\\")
+	default:
+		return value
+	}
+}
+
+func escapeWith(value string, reserved string) string {
+	var result strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(reserved, r) {
+			result.WriteRune('\\')
+		}
+		result.WriteRune(r)
+	}
+
+	return result.String()
+}
+
+var htmlTags = map[string]bool{
+	"b": true, "strong": true, "i": true, "em": true, "u": true, "ins": true,
+	"s": true, "strike": true, "del": true, "span": true, "tg-spoiler": true,
+	"a": true, "code": true, "pre": true, "tg-emoji": true, "blockquote": true,
+}
+
+var htmlEntity = regexp.MustCompile(`^&(lt|gt|amp|quot|#[0-9]+|#x[0-9a-fA-F]+);`)
+
+func validateHTML(text string) error {
+	var open []string
+
+	for i := 0; i < len(text); {
+		switch text[i] {
+		case '<':
+			end := strings.IndexByte(text[i:], '>')
+			if end < 0 {
+				return fmt.Errorf("unescaped \"<\" at character %d, use &lt;", position(text, i))
+			}
+
+			tag := strings.TrimSpace(text[i+1 : i+end])
+			if strings.HasPrefix(tag, "/") {
+				name := strings.ToLower(strings.TrimSpace(tag[1:]))
+				if len(open) == 0 || open[len(open)-1] != name {
+					return fmt.Errorf("unexpected closing tag </%s> at character %d", name, position(text, i))
+				}
+				open = open[:len(open)-1]
+			} else {
+				fields := strings.Fields(tag)
+				if len(fields) == 0 || !htmlTags[strings.ToLower(fields[0])] {
+					return fmt.Errorf("unsupported tag <%s> at character %d", tag, position(text, i))
+				}
+				open = append(open, strings.ToLower(fields[0]))
+			}
+
+			i += end + 1
+		case '>':
+			return fmt.Errorf("unescaped \">\" at character %d, use &gt;", position(text, i))
+		case '&':
+			entity := htmlEntity.FindString(text[i:])
+			if entity == "" {
+				return fmt.Errorf("unescaped \"&\" at character %d, use &amp;", position(text, i))
+			}
+
+			i += len(entity)
+		default:
+			i++
+		}
+	}
+
+	if len(open) > 0 {
+		return fmt.Errorf("unclosed tag <%s>", open[len(open)-1])
+	}
+
+	return nil
+}
+
+// validateMarkdown checks the legacy Markdown mode, its entities cannot be nested.
+func validateMarkdown(text string) error {
+	const escapable = "_*`["
+
+	runes := []rune(text)
+	open := ""
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch open {
+		case "":
+			switch {
+			case r == '\\' && i+1 < len(runes) && strings.ContainsRune(escapable, runes[i+1]):
+				i++
+			case hasPrefix(runes, i, "```"):
+				open = "```"
+				i += 2
+			case strings.ContainsRune(escapable, r):
+				open = string(r)
+			}
+		case "```":
+			if hasPrefix(runes, i, "```") {
+				open = ""
+				i += 2
+			}
+		case "[":
+			if r != ']' {
+				continue
+			}
+
+			end, err := linkEnd(runes, i+1, false)
+			if err != nil {
+				return err
+			}
+			open = ""
+			i = end
+		default:
+			if string(r) == open {
+				open = ""
+			}
+		}
+	}
+
+	if open != "" {
+		return fmt.Errorf("unclosed %q, escape it with \\ if it is not formatting", open)
+	}
+
+	return nil
+}
+
+func validateMarkdownV2(text string) error {
+	const reserved = "_*[]()~`>#+-=|{}.!"
+
+	runes := []rune(text)
+	var open []string
+
+	top := func() string {
+		if len(open) == 0 {
+			return ""
+		}
+		return open[len(open)-1]
+	}
+	toggle := func(delimiter string) {
+		if top() == delimiter {
+			open = open[:len(open)-1]
+		} else {
+			open = append(open, delimiter)
+		}
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if r == '\\' {
+			if i+1 == len(runes) {
+				return fmt.Errorf("trailing \\ at character %d", i+1)
+			}
+			i++
+			continue
+		}
+
+		if top() == "```" || top() == "`" {
+			if hasPrefix(runes, i, top()) {
+				i += len(top()) - 1
+				open = open[:len(open)-1]
+			}
+			continue
+		}
+
+		switch {
+		case hasPrefix(runes, i, "```"):
+			open = append(open, "```")
+			i += 2
+		case r == '`':
+			open = append(open, "`")
+		case hasPrefix(runes, i, "||"):
+			toggle("||")
+			i++
+		case hasPrefix(runes, i, "__"):
+			toggle("__")
+			i++
+		case r == '*' || r == '_' || r == '~':
+			toggle(string(r))
+		case r == '[':
+			open = append(open, "[")
+		case r == ']' && top() == "[":
+			end, err := linkEnd(runes, i+1, true)
+			if err != nil {
+				return err
+			}
+			open = open[:len(open)-1]
+			i = end
+		case r == '>' && (i == 0 || runes[i-1] == '\n'):
+			// Block quotation.
+		case strings.ContainsRune(reserved, r):
+			return fmt.Errorf("character %q at character %d must be escaped with \\", r, i+1)
+		}
+	}
+
+	if len(open) > 0 {
+		return fmt.Errorf("unclosed %q, escape it with \\ if it is not formatting", top())
+	}
+
+	return nil
+}
+
+// linkEnd returns the index of ")" closing the URL of a link which starts at i.
+func linkEnd(runes []rune, i int, escaped bool) (int, error) {
+	if i >= len(runes) || runes[i] != '(' {
+		return 0, fmt.Errorf("link at character %d has no (url)", i)
+	}
+
+	for j := i + 1; j < len(runes); j++ {
+		switch {
+		case escaped && runes[j] == '\\':
+			j++
+		case runes[j] == ')':
+			return j, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unclosed link url at character %d", i+1)
+}
+
+func hasPrefix(runes []rune, i int, prefix string) bool {
+	end := i + len(prefix)
+	if end > len(runes) {
+		return false
+	}
+
+	return string(runes[i:end]) == prefix
+}
+
+func position(text string, i int) int {
+	return utf8.RuneCountInString(text[:i]) + 1
+}
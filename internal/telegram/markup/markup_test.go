@@ -0,0 +1,103 @@
+package markup
+
+import (
+	"DC_NewsSender/internal/telegram/models"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  models.ParseMode
+		text  string
+		valid bool
+	}{
+		{"plain anything", models.ParseModePlain, "<b> *_[ & >", true},
+		{"entities anything", models.ParseModeEntities, "<b> *_[ & >", true},
+
+		{"html plain text", models.ParseModeHTML, "Hello, world!", true},
+		{"html tags", models.ParseModeHTML, "<b>bold</b> <i>italic <u>underline</u></i>", true},
+		{"html link", models.ParseModeHTML, `<a href="https://example.com">link</a>`, true},
+		{"html escaped", models.ParseModeHTML, "1 &lt; 2 &amp;&amp; 3 &gt; 2 &#33; &#x21;", true},
+		{"html spoiler", models.ParseModeHTML, "<tg-spoiler>secret</tg-spoiler>", true},
+		{"html unicode", models.ParseModeHTML, "Привет <b>мир</b> 🎉", true},
+		{"html unescaped less", models.ParseModeHTML, "1 < 2", false},
+		{"html unescaped greater", models.ParseModeHTML, "2 > 1", false},
+		{"html unescaped ampersand", models.ParseModeHTML, "Tom & Jerry", false},
+		{"html unsupported tag", models.ParseModeHTML, "<div>block</div>", false},
+		{"html unclosed tag", models.ParseModeHTML, "<b>bold", false},
+		{"html misnested tags", models.ParseModeHTML, "<b><i>text</b></i>", false},
+		{"html unexpected closing", models.ParseModeHTML, "text</b>", false},
+
+		{"markdown plain text", models.ParseModeMarkdown, "Hello, world.", true},
+		{"markdown entities", models.ParseModeMarkdown, "*bold* _italic_ `code`", true},
+		{"markdown link", models.ParseModeMarkdown, "[link](https://example.com)", true},
+		{"markdown pre", models.ParseModeMarkdown, "```\ncode_with*chars\n```", true},
+		{"markdown escaped", models.ParseModeMarkdown, `snake\_case 2\*2`, true},
+		{"markdown reserved of v2", models.ParseModeMarkdown, "1 + 1 = 2. (ok)!", true},
+		{"markdown unclosed bold", models.ParseModeMarkdown, "*bold", false},
+		{"markdown unclosed underscore", models.ParseModeMarkdown, "snake_case", false},
+		{"markdown unclosed pre", models.ParseModeMarkdown, "```code", false},
+		{"markdown link without url", models.ParseModeMarkdown, "[link] text", false},
+		{"markdown unclosed url", models.ParseModeMarkdown, "[link](https://example.com", false},
+
+		{"markdownv2 plain text", models.ParseModeMarkdownV2, "Hello, world", true},
+		{"markdownv2 entities", models.ParseModeMarkdownV2, "*bold* _italic_ __underline__ ~strike~ ||spoiler||", true},
+		{"markdownv2 nested", models.ParseModeMarkdownV2, "*bold _italic bold_*", true},
+		{"markdownv2 link", models.ParseModeMarkdownV2, "[link](https://example.com/a\\)b)", true},
+		{"markdownv2 code", models.ParseModeMarkdownV2, "`a.b-c` ```\nx = 1.\n```", true},
+		{"markdownv2 escaped", models.ParseModeMarkdownV2, `1 \+ 1 \= 2\. \(ok\)\!`, true},
+		{"markdownv2 quotation", models.ParseModeMarkdownV2, ">quoted\n>lines", true},
+		{"markdownv2 unescaped dot", models.ParseModeMarkdownV2, "The end.", false},
+		{"markdownv2 unescaped dash", models.ParseModeMarkdownV2, "well-known", false},
+		{"markdownv2 unescaped greater", models.ParseModeMarkdownV2, "2 > 1", false},
+		{"markdownv2 unclosed bold", models.ParseModeMarkdownV2, "*bold", false},
+		{"markdownv2 unclosed code", models.ParseModeMarkdownV2, "`code", false},
+		{"markdownv2 trailing backslash", models.ParseModeMarkdownV2, `text\`, false},
+		{"markdownv2 link without url", models.ParseModeMarkdownV2, "[link] text", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(test.mode, test.text)
+			if test.valid && err != nil {
+				t.Errorf("Validate(%s, %q) = %v, want valid", test.mode, test.text, err)
+			}
+			if !test.valid && err == nil {
+				t.Errorf("Validate(%s, %q) = nil, want an error", test.mode, test.text)
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  models.ParseMode
+		value string
+		want  string
+	}{
+		{"plain", models.ParseModePlain, "<*_>", "<*_>"},
+		{"html", models.ParseModeHTML, `<a href="x">&</a>`, "&lt;a href=&#34;x&#34;&gt;&amp;&lt;/a&gt;"},
+		{"markdown", models.ParseModeMarkdown, "snake_case *[x]* `y`", "snake\\_case \\*\\[x]\\* \\`y\\`"},
+		{"markdownv2", models.ParseModeMarkdownV2, "a.b-c (1+1=2)!", "a\\.b\\-c \\(1\\+1\\=2\\)\\!"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Escape(test.mode, test.value); got != test.want {
+				t.Errorf("Escape(%s, %q) = %q, want %q", test.mode, test.value, got, test.want)
+			}
+		})
+	}
+}
+
+func TestEscapedValuesAreValid(t *testing.T) {
+	const value = `Tom & Jerry <3 *snake_case* [x](y) 1+1=2. {a|b} ~c~ #d! \e`
+
+	for _, mode := range models.ParseModes {
+		if err := Validate(mode, Escape(mode, value)); err != nil {
+			t.Errorf("Validate(%s, Escape(%q)) = %v, want valid", mode, value, err)
+		}
+	}
+}
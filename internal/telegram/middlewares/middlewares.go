@@ -5,23 +5,29 @@ import (
 	"DC_NewsSender/internal/telegram/models"
 
 	tele "gopkg.in/telebot.v3"
-	"gopkg.in/telebot.v3/middleware"
 )
 
+// Whitelist lets through updates from the chats of admins only. The admins are looked up
+// in the cache on every update, so added and removed admins take effect at once.
 func Whitelist(s controller.IService[models.User, int64]) tele.MiddlewareFunc {
-	admins, _ := s.FindAll()
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			if c.Chat() == nil {
+				return nil
+			}
 
-	var adminsIds []int64
+			admins, err := s.FindAll()
+			if err != nil {
+				return err
+			}
 
-	for _, admin := range admins {
-		adminsIds = append(adminsIds, admin.Id)
-	}
+			for _, admin := range admins {
+				if admin.Id == c.Chat().ID {
+					return next(c)
+				}
+			}
 
-	return func(next tele.HandlerFunc) tele.HandlerFunc {
-		return middleware.Restrict(middleware.RestrictConfig{
-			Chats: adminsIds,
-			In:    next,
-			Out:   func(c tele.Context) error { return nil },
-		})(next)
+			return nil
+		}
 	}
 }
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"DC_NewsSender/internal/db/models"
+)
+
+type Approval models.Approval
+
+const (
+	ApprovalStatusPending  string = "pending"
+	ApprovalStatusApproved string = "approved"
+	ApprovalStatusRejected string = "rejected"
+	ApprovalStatusExpired  string = "expired"
+)
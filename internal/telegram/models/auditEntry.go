@@ -0,0 +1,7 @@
+package models
+
+import (
+	"DC_NewsSender/internal/db/models"
+)
+
+type AuditEntry models.AuditEntry
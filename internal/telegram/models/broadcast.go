@@ -0,0 +1,16 @@
+package models
+
+import (
+	"DC_NewsSender/internal/db/models"
+)
+
+type Broadcast models.Broadcast
+
+const (
+	BroadcastStatusRunning string = "running"
+	BroadcastStatusDone    string = "done"
+	// BroadcastStatusInterrupted marks a broadcast the process stopped in the middle of.
+	BroadcastStatusInterrupted string = "interrupted"
+	// BroadcastStatusCancelled marks a broadcast recalled before it reached every chat.
+	BroadcastStatusCancelled string = "cancelled"
+)
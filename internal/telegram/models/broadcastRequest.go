@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// BroadcastRequest is a broadcast waiting for its initiator to confirm the resolved chats.
+type BroadcastRequest struct {
+	Id          string
+	InitiatorId int64
+	MessageId   uint64
+	Target      Target
+	CreatedAt   time.Time
+}
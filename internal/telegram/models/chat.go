@@ -5,3 +5,14 @@ import (
 )
 
 type Chat models.Chat
+
+// InGroup reports whether the chat is a member of the group.
+func (c *Chat) InGroup(groupId uint64) bool {
+	for _, group := range c.Groups {
+		if group.Id == groupId {
+			return true
+		}
+	}
+
+	return false
+}
@@ -10,4 +10,15 @@ type Command struct {
 type Arguments struct {
 	Names []string
 	Types []reflect.Kind
+	// Variadic passes the last argument and every part of the input after it
+	// as a []string, so it can be followed by any number of parts.
+	Variadic bool
+	// Optional is how many of the last arguments may be left out,
+	// the handler gets no value for them.
+	Optional int
+}
+
+// Required is how many arguments the input must have.
+func (a Arguments) Required() int {
+	return len(a.Names) - a.Optional
 }
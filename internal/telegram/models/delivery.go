@@ -0,0 +1,22 @@
+package models
+
+import (
+	"DC_NewsSender/internal/db/models"
+)
+
+type Delivery models.Delivery
+
+const (
+	DeliveryStatusSent    string = "sent"
+	DeliveryStatusSkipped string = "skipped"
+	DeliveryStatusFailed  string = "failed"
+	// DeliveryStatusRecalled marks a sent message that was deleted afterwards.
+	DeliveryStatusRecalled string = "recalled"
+)
+
+const (
+	SkipReasonInactive        string = "inactive chat"
+	SkipReasonMissingLanguage string = "missing language"
+	// SkipReasonCancelled marks a chat the broadcast was recalled before it reached.
+	SkipReasonCancelled string = "cancelled"
+)
@@ -1,29 +1,131 @@
 package models
 
+import tele "gopkg.in/telebot.v3"
+
 type Message struct {
-	Id    uint64
-	Text  map[uint64]string
-	Photo string
+	Id        uint64
+	ParseMode ParseMode
+	Text      map[uint64]string
+	// Entities holds the formatting of the text of every language.
+	Entities map[uint64]tele.Entities
+	// Buttons holds the inline keyboard of every language.
+	Buttons map[uint64][][]tele.InlineButton
+	// Media holds the files of every language, SharedMedia holds the default ones.
+	Media map[uint64][]Media
+}
+
+// SharedMedia is the Media key of files sent to languages without media of their own.
+const SharedMedia uint64 = 0
+
+// Media is a file attached to the message, referenced by its Telegram file id.
+type Media struct {
+	Type   MessageType
+	FileID string
 }
 
+// ParseMode is how the text of a message is formatted when it is sent.
+type ParseMode string
+
+const (
+	// ParseModeEntities keeps the formatting typed in the Telegram client.
+	ParseModeEntities   ParseMode = "entities"
+	ParseModePlain      ParseMode = "plain"
+	ParseModeMarkdown   ParseMode = "markdown"
+	ParseModeMarkdownV2 ParseMode = "markdownv2"
+	ParseModeHTML       ParseMode = "html"
+)
+
+// ParseModes are the modes which can be chosen in the message pattern.
+var ParseModes = []ParseMode{ParseModeEntities, ParseModePlain, ParseModeMarkdown, ParseModeMarkdownV2, ParseModeHTML}
+
 type MessageType int
 
 const (
 	TextMessage MessageType = iota
 	PhotoMessage
+	VideoMessage
+	DocumentMessage
+	AnimationMessage
+	AudioMessage
+	VoiceMessage
+	// AlbumMessage is a media group of several photos, videos, documents or audios.
+	AlbumMessage
 )
 
-func (m *Message) GetType() MessageType {
-	if m.Photo != "" {
-		return PhotoMessage
+// MediaFor returns the files of the language, or the shared ones if it has none.
+func (m *Message) MediaFor(languageId uint64) []Media {
+	if media, ok := m.Media[languageId]; ok {
+		return media
+	}
+
+	return m.Media[SharedMedia]
+}
+
+func (m *Message) GetType(languageId uint64) MessageType {
+	media := m.MediaFor(languageId)
+
+	switch len(media) {
+	case 0:
+		return TextMessage
+	case 1:
+		return media[0].Type
+	default:
+		return AlbumMessage
+	}
+}
+
+// SendOptions formats the text of the language by the parse mode of the message
+// and attaches its buttons.
+func (m *Message) SendOptions(languageId uint64) *tele.SendOptions {
+	opts := &tele.SendOptions{}
+
+	switch m.ParseMode {
+	case ParseModeEntities:
+		opts.Entities = m.Entities[languageId]
+	case ParseModeMarkdown:
+		opts.ParseMode = tele.ModeMarkdown
+	case ParseModeMarkdownV2:
+		opts.ParseMode = tele.ModeMarkdownV2
+	case ParseModeHTML:
+		opts.ParseMode = tele.ModeHTML
 	}
 
-	return TextMessage
+	if buttons := m.Buttons[languageId]; len(buttons) > 0 {
+		opts.ReplyMarkup = &tele.ReplyMarkup{InlineKeyboard: buttons}
+	}
+
+	return opts
 }
 
 func CreateMessage() *Message {
 	return &Message{
-		Text:  make(map[uint64]string),
-		Photo: "",
+		ParseMode: ParseModeEntities,
+		Text:      make(map[uint64]string),
+		Entities:  make(map[uint64]tele.Entities),
+		Buttons:   make(map[uint64][][]tele.InlineButton),
+		Media:     make(map[uint64][]Media),
+	}
+}
+
+// Clone copies the message with maps of its own,
+// so changing the copy does not touch the cached message.
+func (m *Message) Clone() *Message {
+	clone := CreateMessage()
+	clone.Id = m.Id
+	clone.ParseMode = m.ParseMode
+
+	for key, value := range m.Text {
+		clone.Text[key] = value
+	}
+	for key, value := range m.Entities {
+		clone.Entities[key] = value
 	}
+	for key, value := range m.Buttons {
+		clone.Buttons[key] = value
+	}
+	for key, value := range m.Media {
+		clone.Media[key] = value
+	}
+
+	return clone
 }
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"DC_NewsSender/internal/db/models"
+	"sort"
+)
+
+type RoleGrant models.RoleGrant
+
+// Permission is what a command or a handler requires of the initiator.
+type Permission string
+
+const (
+	PermissionStash   Permission = "stash"
+	PermissionPreview Permission = "preview"
+	// PermissionSend allows broadcasts to the group of the role, or to any chats for a role without a group.
+	PermissionSend        Permission = "send"
+	PermissionManageChats Permission = "manage_chats"
+	// PermissionMaster is held by masters only, it guards admins, roles, languages and groups.
+	PermissionMaster Permission = "master"
+)
+
+const (
+	RoleEditor  string = "editor"
+	RoleSender  string = "sender"
+	RoleManager string = "manager"
+)
+
+// Roles lists the permissions every role grants.
+var Roles = map[string][]Permission{
+	RoleEditor:  {PermissionStash, PermissionPreview},
+	RoleSender:  {PermissionPreview, PermissionSend},
+	RoleManager: {PermissionManageChats},
+}
+
+// RoleNames returns the sorted names of the roles.
+func RoleNames() []string {
+	names := make([]string, 0, len(Roles))
+	for name := range Roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Grants reports whether the role of the grant has the permission in the group,
+// groupId 0 asks for the permission in every group.
+func (g RoleGrant) Grants(permission Permission, groupId uint64) bool {
+	if !contains(Roles[g.Role], permission) {
+		return false
+	}
+
+	return g.GroupId == 0 || g.GroupId == groupId
+}
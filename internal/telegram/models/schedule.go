@@ -0,0 +1,15 @@
+package models
+
+import (
+	"DC_NewsSender/internal/db/models"
+)
+
+type Schedule models.Schedule
+
+const (
+	ScheduleStatusPending   string = "pending"
+	ScheduleStatusSent      string = "sent"
+	ScheduleStatusFailed    string = "failed"
+	ScheduleStatusMissed    string = "missed"
+	ScheduleStatusCancelled string = "cancelled"
+)
@@ -0,0 +1,177 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Terms of a broadcast target, e.g. "groups=2,3;lang=1;exclude=-100123".
+const (
+	TargetAll      string = "all"
+	TargetGroups   string = "groups"
+	TargetChats    string = "chats"
+	TargetLanguage string = "lang"
+	TargetExclude  string = "exclude"
+	// TargetStrict skips chats without a text in their language instead of using a fallback.
+	TargetStrict string = "strict"
+)
+
+// Target selects the chats a broadcast is delivered to: every chat, the chats
+// of the groups and the listed chats, narrowed down to the languages if any
+// are given and without the excluded chats.
+type Target struct {
+	All         bool
+	GroupIds    []uint64
+	ChatIds     []int64
+	LanguageIds []uint64
+	Exclude     []int64
+	Strict      bool
+}
+
+// ParseTarget parses the target terms, a bare number is a group id.
+func ParseTarget(terms []string) (*Target, error) {
+	target := &Target{}
+
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		if term == TargetAll {
+			target.All = true
+			continue
+		}
+
+		if term == TargetStrict {
+			target.Strict = true
+			continue
+		}
+
+		if groupId, err := strconv.ParseUint(term, 10, 64); err == nil {
+			target.GroupIds = append(target.GroupIds, groupId)
+			continue
+		}
+
+		key, value, ok := strings.Cut(term, "=")
+		if !ok {
+			return nil, fmt.Errorf("unknown target %q", term)
+		}
+
+		var err error
+		switch strings.TrimSpace(key) {
+		case TargetGroups:
+			target.GroupIds, err = appendIds(target.GroupIds, value, parseUint)
+		case TargetChats:
+			target.ChatIds, err = appendIds(target.ChatIds, value, parseInt)
+		case TargetLanguage:
+			target.LanguageIds, err = appendIds(target.LanguageIds, value, parseUint)
+		case TargetExclude:
+			target.Exclude, err = appendIds(target.Exclude, value, parseInt)
+		default:
+			return nil, fmt.Errorf("unknown target %q, use %s, %s=, %s=, %s= or %s=", key, TargetAll, TargetGroups, TargetChats, TargetLanguage, TargetExclude)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", key, value)
+		}
+	}
+
+	if !target.All && len(target.GroupIds) == 0 && len(target.ChatIds) == 0 {
+		return nil, fmt.Errorf("no chats selected, use %s, %s= or %s=", TargetAll, TargetGroups, TargetChats)
+	}
+
+	return target, nil
+}
+
+// Selects reports whether the chat is one of the target chats.
+func (t *Target) Selects(chat Chat) bool {
+	if contains(t.Exclude, chat.Id) {
+		return false
+	}
+
+	if len(t.LanguageIds) > 0 && !contains(t.LanguageIds, chat.LanguageId) {
+		return false
+	}
+
+	return t.All || contains(t.ChatIds, chat.Id) || t.GroupOf(chat) != 0
+}
+
+// GroupOf returns the first target group the chat is a member of, or 0.
+func (t *Target) GroupOf(chat Chat) uint64 {
+	for _, groupId := range t.GroupIds {
+		if chat.InGroup(groupId) {
+			return groupId
+		}
+	}
+
+	return 0
+}
+
+// String formats the target in the syntax ParseTarget accepts.
+func (t *Target) String() string {
+	var terms []string
+
+	if t.All {
+		terms = append(terms, TargetAll)
+	}
+	if len(t.GroupIds) > 0 {
+		terms = append(terms, TargetGroups+"="+joinIds(t.GroupIds))
+	}
+	if len(t.ChatIds) > 0 {
+		terms = append(terms, TargetChats+"="+joinIds(t.ChatIds))
+	}
+	if len(t.LanguageIds) > 0 {
+		terms = append(terms, TargetLanguage+"="+joinIds(t.LanguageIds))
+	}
+	if len(t.Exclude) > 0 {
+		terms = append(terms, TargetExclude+"="+joinIds(t.Exclude))
+	}
+	if t.Strict {
+		terms = append(terms, TargetStrict)
+	}
+
+	return strings.Join(terms, ";")
+}
+
+func appendIds[T comparable](ids []T, value string, parse func(string) (T, error)) ([]T, error) {
+	for _, part := range strings.Split(value, ",") {
+		id, err := parse(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+
+		if !contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+func parseUint(value string) (uint64, error) {
+	return strconv.ParseUint(value, 10, 64)
+}
+
+func parseInt(value string) (int64, error) {
+	return strconv.ParseInt(value, 10, 64)
+}
+
+func joinIds[T any](ids []T) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprint(id))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func contains[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
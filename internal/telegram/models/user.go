@@ -12,3 +12,55 @@ type User struct {
 func CreateUser(id int64, name string) *User {
 	return &User{Admin: db_models.Admin{Id: id, Name: name}}
 }
+
+// Can reports whether the user has the permission at least in one group,
+// masters have every permission.
+func (u *User) Can(permission Permission) bool {
+	if u.IsMaster {
+		return true
+	}
+
+	for _, grant := range u.Roles {
+		if contains(Roles[grant.Role], permission) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanIn reports whether the user has the permission in the group,
+// groupId 0 asks for the permission in every group.
+func (u *User) CanIn(permission Permission, groupId uint64) bool {
+	if u.IsMaster {
+		return true
+	}
+
+	for _, grant := range u.Roles {
+		if RoleGrant(grant).Grants(permission, groupId) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanTarget reports whether the user has the permission for every chat of the target.
+// A role limited to groups only covers targets which select chats by these groups.
+func (u *User) CanTarget(permission Permission, target *Target) bool {
+	if u.CanIn(permission, 0) {
+		return true
+	}
+
+	if target.All || len(target.ChatIds) > 0 || len(target.GroupIds) == 0 {
+		return false
+	}
+
+	for _, groupId := range target.GroupIds {
+		if !u.CanIn(permission, groupId) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/controller"
+	"DC_NewsSender/internal/telegram/models"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Scheduler struct {
+	controller *controller.Controller
+	logger     *zap.Logger
+}
+
+func CreateScheduler(controller *controller.Controller) *Scheduler {
+	return &Scheduler{
+		controller: controller,
+		logger:     controller.Logger.With(zap.String("service", "Scheduler")),
+	}
+}
+
+// Start reports schedules missed during downtime and begins polling for due ones
+// and for approvals which have expired.
+func (s *Scheduler) Start() {
+	s.logger.Info("Starting scheduler")
+
+	s.reportMissed(time.Now())
+
+	go func() {
+		ticker := time.NewTicker(constants.ScheduleInterval)
+		defer ticker.Stop()
+
+		s.fireDue(time.Now())
+		s.controller.ExpireApprovals(time.Now())
+		for now := range ticker.C {
+			s.fireDue(now)
+			s.controller.ExpireApprovals(now)
+		}
+	}()
+}
+
+// reportMissed marks pending schedules that are older than the grace period as missed.
+func (s *Scheduler) reportMissed(now time.Time) {
+	logger := s.logger.With(
+		zap.String("function", "reportMissed"),
+	)
+
+	scheduleService := s.controller.CreateScheduleService()
+
+	for _, schedule := range s.pending() {
+		if now.Sub(schedule.SendAt) <= constants.ScheduleMissedGrace {
+			continue
+		}
+
+		schedule.Status = models.ScheduleStatusMissed
+		schedule.Result = "bot was offline at the scheduled time"
+		if _, err := scheduleService.Update(&schedule); err != nil {
+			logger.Error("Failed to mark schedule as missed", zap.Any("schedule", schedule), zap.Error(err))
+			continue
+		}
+
+		logger.Warn("Schedule missed", zap.Any("schedule", schedule))
+
+		s.controller.Notify(schedule.InitiatorId, fmt.Sprintf(
+			"Schedule [%d] missed: message [%d] to group [%d] was due at %s.",
+			schedule.Id, schedule.MessageId, schedule.GroupId, schedule.SendAt.Format(constants.ScheduleTimeLayout)))
+	}
+}
+
+// fireDue broadcasts every pending schedule whose time has come,
+// schedules of admins who are not masters wait for an approval first.
+func (s *Scheduler) fireDue(now time.Time) {
+	logger := s.logger.With(
+		zap.String("function", "fireDue"),
+	)
+
+	scheduleService := s.controller.CreateScheduleService()
+
+	for _, schedule := range s.pending() {
+		if schedule.SendAt.After(now) {
+			continue
+		}
+
+		logger.Debug("Firing schedule", zap.Any("schedule", schedule))
+
+		response, err := s.controller.StartBroadcast(schedule.InitiatorId, schedule.MessageId, &models.Target{GroupIds: []uint64{schedule.GroupId}})
+		if err != nil {
+			schedule.Status = models.ScheduleStatusFailed
+			schedule.Result = err.Error()
+			response = fmt.Sprintf("Error: %s", err.Error())
+		} else {
+			schedule.Status = models.ScheduleStatusSent
+			schedule.Result = response
+		}
+
+		if _, err := scheduleService.Update(&schedule); err != nil {
+			logger.Error("Failed to update schedule", zap.Any("schedule", schedule), zap.Error(err))
+		}
+
+		s.controller.Notify(schedule.InitiatorId, fmt.Sprintf("Schedule [%d]:\n%s", schedule.Id, response))
+	}
+}
+
+func (s *Scheduler) pending() []models.Schedule {
+	schedules, _ := s.controller.CreateScheduleService().FindAll()
+
+	var result []models.Schedule
+	for _, schedule := range schedules {
+		if schedule.Status == models.ScheduleStatusPending {
+			result = append(result, schedule)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].SendAt.Before(result[j].SendAt) })
+
+	return result
+}
@@ -2,9 +2,13 @@ package telegram
 
 import (
 	"DC_NewsSender/internal/db/repositories"
+	"DC_NewsSender/internal/telegram/constants"
 	"DC_NewsSender/internal/telegram/controller"
+	"DC_NewsSender/internal/telegram/delivery"
 	"DC_NewsSender/internal/telegram/handlers"
 	"DC_NewsSender/internal/telegram/middlewares"
+	"DC_NewsSender/internal/telegram/models"
+	"DC_NewsSender/internal/telegram/scheduler"
 	"time"
 
 	tele "gopkg.in/telebot.v3"
@@ -17,10 +21,12 @@ type Core struct {
 }
 
 type BotConfig struct {
-	Token  string
-	Logger *zap.Logger
-	Db     *repositories.Provider
-	Debug  bool
+	Token       string
+	Logger      *zap.Logger
+	Db          *repositories.Provider
+	Debug       bool
+	Approvers   []int64
+	ApprovalTTL time.Duration
 }
 
 func CreateBotCore(cfg *BotConfig) (*Core, error) {
@@ -34,7 +40,14 @@ func CreateBotCore(cfg *BotConfig) (*Core, error) {
 
 	logger := cfg.Logger
 
-	controller := &controller.Controller{Bot: bot, Provider: db, Logger: logger}
+	controller := &controller.Controller{
+		Bot:         bot,
+		Provider:    db,
+		Logger:      logger,
+		Delivery:    delivery.CreateEngine(logger),
+		Approvers:   cfg.Approvers,
+		ApprovalTTL: cfg.ApprovalTTL,
+	}
 
 	return &Core{controller: controller}, nil
 }
@@ -42,8 +55,16 @@ func CreateBotCore(cfg *BotConfig) (*Core, error) {
 func (c *Core) Run() {
 	c.controller.UpdateCache()
 
+	c.controller.InterruptBroadcasts()
+
+	if err := c.controller.CheckApprovers(); err != nil {
+		panic(err)
+	}
+
 	c.handleUpdates()
 
+	scheduler.CreateScheduler(c.controller).Start()
+
 	c.controller.Bot.Start()
 }
 
@@ -58,6 +79,22 @@ func (bot *Core) handleUpdates() {
 
 	msgHandler := handlers.CreateMessageHandler(bot.controller)
 
+	chatHandler := handlers.CreateChatHandler(bot.controller)
+
+	broadcastHandler := handlers.CreateBroadcastHandler(bot.controller)
+
+	for _, endpoint := range []string{tele.OnAddedToGroup, tele.OnMyChatMember} {
+		bot.controller.Bot.Handle(endpoint, func(c tele.Context) error {
+			chatHandler.HandleAdded(c)
+			return nil
+		})
+	}
+
+	bot.controller.Bot.Handle(tele.OnMigration, func(c tele.Context) error {
+		chatHandler.HandleMigration(c)
+		return nil
+	})
+
 	adminOnly := bot.controller.Bot.Group()
 
 	adminOnly.Use(middlewares.Whitelist(bot.controller.CreateUserService()))
@@ -75,15 +112,41 @@ func (bot *Core) handleUpdates() {
 		return nil
 	})
 
-	adminOnly.Handle(tele.OnPhoto, func(c tele.Context) error {
-		user, err := bot.controller.CreateUserService().FindById(c.Sender().ID)
-		if err != nil {
-			bot.controller.Logger.Error(err.Error())
+	for _, endpoint := range []string{tele.OnPhoto, tele.OnVideo, tele.OnDocument, tele.OnAnimation, tele.OnAudio, tele.OnVoice} {
+		adminOnly.Handle(endpoint, func(c tele.Context) error {
+			user, err := bot.controller.CreateUserService().FindById(c.Sender().ID)
+			if err != nil {
+				bot.controller.Logger.Error(err.Error())
 
-			return err
-		}
+				return err
+			}
 
-		msgHandler.HandleMessage(user, c)
-		return nil
-	})
+			msgHandler.HandleMessage(user, c)
+			return nil
+		})
+	}
+
+	callbacks := map[string]func(*models.User, tele.Context){
+		constants.CallbackChatLanguage:     chatHandler.HandleLanguage,
+		constants.CallbackChatGroup:        chatHandler.HandleGroup,
+		constants.CallbackBroadcastConfirm: broadcastHandler.HandleConfirm,
+		constants.CallbackBroadcastCancel:  broadcastHandler.HandleCancel,
+		constants.CallbackBroadcastApprove: broadcastHandler.HandleApprove,
+		constants.CallbackBroadcastReject:  broadcastHandler.HandleReject,
+	}
+
+	for unique, handle := range callbacks {
+		handle := handle
+		adminOnly.Handle(&tele.InlineButton{Unique: unique}, func(c tele.Context) error {
+			user, err := bot.controller.CreateUserService().FindById(c.Sender().ID)
+			if err != nil {
+				bot.controller.Logger.Error(err.Error())
+
+				return err
+			}
+
+			handle(user, c)
+			return nil
+		})
+	}
 }
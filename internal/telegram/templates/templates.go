@@ -0,0 +1,165 @@
+package templates
+
+import (
+	"DC_NewsSender/internal/telegram/constants"
+	"DC_NewsSender/internal/telegram/markup"
+	"DC_NewsSender/internal/telegram/models"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf16"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+// placeholder matches a single action such as {{chat.name}} or {{var "region" "Europe"}}.
+// Every placeholder is a template of its own, so control structures are not supported.
+var placeholder = regexp.MustCompile(`(?s)\{\{.*?\}\}`)
+
+// Data is what the placeholders of a text refer to for one recipient.
+type Data struct {
+	ChatId       int64
+	ChatName     string
+	GroupId      uint64
+	GroupName    string
+	LanguageId   uint64
+	LanguageName string
+	Date         time.Time
+	Variables    map[string]string
+}
+
+// SampleData is used to validate texts before any recipient is known.
+func SampleData() Data {
+	return Data{
+		ChatId:       -1,
+		ChatName:     "Chat",
+		GroupId:      1,
+		GroupName:    "Group",
+		LanguageId:   1,
+		LanguageName: "Language",
+		Date:         time.Now(),
+	}
+}
+
+// funcs is the whole function set placeholders can use, values are plain strings.
+func (d Data) funcs() template.FuncMap {
+	return template.FuncMap{
+		"chat": func() map[string]string {
+			return map[string]string{"id": strconv.FormatInt(d.ChatId, 10), "name": d.ChatName}
+		},
+		"group": func() map[string]string {
+			return map[string]string{"id": strconv.FormatUint(d.GroupId, 10), "name": d.GroupName}
+		},
+		"language": func() map[string]string {
+			return map[string]string{"id": strconv.FormatUint(d.LanguageId, 10), "name": d.LanguageName}
+		},
+		"date": func(layout ...string) string {
+			if len(layout) > 0 {
+				return d.Date.Format(layout[0])
+			}
+			return d.Date.Format(constants.TemplateDateLayout)
+		},
+		"var": func(key string, fallback ...string) string {
+			if value, ok := d.Variables[key]; ok {
+				return value
+			}
+			if len(fallback) > 0 {
+				return fallback[0]
+			}
+			return ""
+		},
+	}
+}
+
+// HasPlaceholders reports whether the text has to be rendered for every recipient.
+func HasPlaceholders(text string) bool {
+	return placeholder.MatchString(text)
+}
+
+// Validate renders the placeholders of the text with SampleData,
+// so mistakes show up when the message is stashed and not while it is broadcast.
+func Validate(text string) error {
+	_, _, err := Render(text, nil, models.ParseModePlain, SampleData())
+	return err
+}
+
+// Render replaces the placeholders of the text with the values of the data escaped
+// for the parse mode. Entities are moved to match the rendered text.
+func Render(text string, entities tele.Entities, mode models.ParseMode, data Data) (string, tele.Entities, error) {
+	matches := placeholder.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return text, entities, nil
+	}
+
+	type edit struct {
+		offset, length, rendered int
+	}
+
+	var result strings.Builder
+	var edits []edit
+	last := 0
+
+	for _, match := range matches {
+		source := text[match[0]:match[1]]
+
+		tmpl, err := template.New("").Option("missingkey=error").Funcs(data.funcs()).Parse(source)
+		if err != nil {
+			return "", nil, fmt.Errorf("placeholder %s: %w", source, err)
+		}
+
+		var value strings.Builder
+		if err := tmpl.Execute(&value, nil); err != nil {
+			return "", nil, fmt.Errorf("placeholder %s: %w", source, err)
+		}
+		rendered := markup.Escape(mode, value.String())
+
+		result.WriteString(text[last:match[0]])
+		edits = append(edits, edit{
+			offset:   utf16Len(text[:match[0]]),
+			length:   utf16Len(source),
+			rendered: utf16Len(rendered),
+		})
+		result.WriteString(rendered)
+		last = match[1]
+	}
+	result.WriteString(text[last:])
+
+	// position maps an UTF-16 position of the text to the rendered text,
+	// an entity around a placeholder spans its whole value.
+	position := func(p int, end bool) int {
+		delta := 0
+		for _, e := range edits {
+			switch {
+			case p <= e.offset:
+				return p + delta
+			case p < e.offset+e.length:
+				if end {
+					return e.offset + delta + e.rendered
+				}
+				return e.offset + delta
+			}
+			delta += e.rendered - e.length
+		}
+		return p + delta
+	}
+
+	moved := make(tele.Entities, 0, len(entities))
+	for _, entity := range entities {
+		start, end := position(entity.Offset, false), position(entity.Offset+entity.Length, true)
+		if end <= start {
+			continue
+		}
+
+		entity.Offset, entity.Length = start, end-start
+		moved = append(moved, entity)
+	}
+
+	return result.String(), moved, nil
+}
+
+func utf16Len(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
@@ -0,0 +1,180 @@
+package templates
+
+import (
+	"DC_NewsSender/internal/telegram/models"
+	"reflect"
+	"testing"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func testData() Data {
+	return Data{
+		ChatId:       -100,
+		ChatName:     "😀 Chat",
+		GroupId:      7,
+		GroupName:    "Group",
+		LanguageId:   2,
+		LanguageName: "English",
+		Date:         time.Date(2026, time.March, 14, 15, 9, 26, 0, time.UTC),
+		Variables:    map[string]string{"region": "Asia"},
+	}
+}
+
+func bold(offset, length int) tele.MessageEntity {
+	return tele.MessageEntity{Type: tele.EntityBold, Offset: offset, Length: length}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		entities     tele.Entities
+		want         string
+		wantEntities tele.Entities
+	}{
+		{
+			name: "no placeholders",
+			text: "Hello 😀", entities: tele.Entities{bold(0, 5)},
+			want: "Hello 😀", wantEntities: tele.Entities{bold(0, 5)},
+		},
+		{
+			name: "every function",
+			text: `{{chat.id}} {{chat.name}} {{group.id}} {{group.name}} {{language.id}} {{language.name}} {{date}} {{date "2006"}}`,
+			want: "-100 😀 Chat 7 Group 2 English 2026-03-14 2026",
+		},
+		{
+			name: "placeholder before entity",
+			text: "{{chat.name}} bold", entities: tele.Entities{bold(14, 4)},
+			want: "😀 Chat bold", wantEntities: tele.Entities{bold(8, 4)},
+		},
+		{
+			name: "placeholder inside entity",
+			text: "Hi {{chat.name}}!", entities: tele.Entities{bold(0, 17)},
+			want: "Hi 😀 Chat!", wantEntities: tele.Entities{bold(0, 11)},
+		},
+		{
+			name: "placeholder after entity",
+			text: "Bold {{chat.name}}", entities: tele.Entities{bold(0, 4)},
+			want: "Bold 😀 Chat", wantEntities: tele.Entities{bold(0, 4)},
+		},
+		{
+			name: "entity overlapping placeholder",
+			text: "Hi {{chat.name}}!", entities: tele.Entities{bold(5, 3), bold(10, 7)},
+			want: "Hi 😀 Chat!", wantEntities: tele.Entities{bold(3, 7), bold(3, 8)},
+		},
+		{
+			name: "surrogate pair before placeholder",
+			text: "😀 {{group.name}} x", entities: tele.Entities{bold(18, 1)},
+			want: "😀 Group x", wantEntities: tele.Entities{bold(9, 1)},
+		},
+		{
+			name: "several placeholders",
+			text: "{{group.name}}, {{chat.name}}: news", entities: tele.Entities{bold(31, 4)},
+			want: "Group, 😀 Chat: news", wantEntities: tele.Entities{bold(16, 4)},
+		},
+		{
+			name: "entity of an empty value",
+			text: `a {{var "missing"}} b`, entities: tele.Entities{bold(2, 17), bold(0, 21)},
+			want: "a  b", wantEntities: tele.Entities{bold(0, 4)},
+		},
+		{
+			name: "variables",
+			text: `{{var "region"}} {{var "missing" "Europe"}} [{{var "missing"}}]`,
+			want: "Asia Europe []",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, gotEntities, err := Render(test.text, test.entities, models.ParseModeEntities, testData())
+			if err != nil {
+				t.Fatalf("Render(%q) error = %v", test.text, err)
+			}
+			if got != test.want {
+				t.Errorf("Render(%q) = %q, want %q", test.text, got, test.want)
+			}
+			if len(gotEntities) != 0 || len(test.wantEntities) != 0 {
+				if !reflect.DeepEqual(gotEntities, test.wantEntities) {
+					t.Errorf("Render(%q) entities = %v, want %v", test.text, gotEntities, test.wantEntities)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderEscapes(t *testing.T) {
+	data := testData()
+	data.ChatName = "<a.b & c>"
+
+	tests := []struct {
+		mode models.ParseMode
+		want string
+	}{
+		{models.ParseModePlain, "<a.b & c>"},
+		{models.ParseModeHTML, "<b>&lt;a.b &amp; c&gt;</b>"},
+		{models.ParseModeMarkdownV2, "*<a\\.b & c\\>*"},
+	}
+
+	templates := map[models.ParseMode]string{
+		models.ParseModePlain:      "{{chat.name}}",
+		models.ParseModeHTML:       "<b>{{chat.name}}</b>",
+		models.ParseModeMarkdownV2: "*{{chat.name}}*",
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.mode), func(t *testing.T) {
+			got, _, err := Render(templates[test.mode], nil, test.mode, data)
+			if err != nil {
+				t.Fatalf("Render error = %v", err)
+			}
+			if got != test.want {
+				t.Errorf("Render = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		valid bool
+	}{
+		{"plain text", "Hello", true},
+		{"known placeholders", `{{chat.name}} {{group.id}} {{date "02.01"}}`, true},
+		{"unknown variable", `{{var "unknown"}}`, true},
+		{"unknown function", "{{unknown}}", false},
+		{"unknown field", "{{chat.title}}", false},
+		{"unterminated string", `{{var "region}}`, false},
+		{"wrong argument", "{{var 1}}", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(test.text)
+			if test.valid && err != nil {
+				t.Errorf("Validate(%q) = %v, want valid", test.text, err)
+			}
+			if !test.valid && err == nil {
+				t.Errorf("Validate(%q) = nil, want an error", test.text)
+			}
+		})
+	}
+}
+
+func TestHasPlaceholders(t *testing.T) {
+	tests := map[string]bool{
+		"Hello":               false,
+		"{not a placeholder}": false,
+		"Hi {{chat.name}}":    true,
+		"{{\nchat.name\n}}":   true,
+	}
+
+	for text, want := range tests {
+		if got := HasPlaceholders(text); got != want {
+			t.Errorf("HasPlaceholders(%q) = %v, want %v", text, got, want)
+		}
+	}
+}